@@ -97,7 +97,7 @@ func RunSingleValidation(ctx context.Context, validationBytes []byte, opts ...ty
 		return lulaValidation, err
 	}
 
-	lulaValidation, err = validation.ToLulaValidation("")
+	lulaValidation, err = validation.ToLulaValidation(ctx, "")
 	if err != nil {
 		return lulaValidation, err
 	}
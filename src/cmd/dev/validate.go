@@ -109,7 +109,7 @@ func DevValidateCommand() *cobra.Command {
 			if len(validation.Result.Observations) > 0 {
 				message.Infof("Observations:")
 				for key, observation := range validation.Result.Observations {
-					message.Infof("--> %s: %s", key, observation)
+					message.Infof("--> %s: %s", key, types.FormatObservation(observation))
 				}
 			}
 
@@ -124,7 +124,10 @@ func DevValidateCommand() *cobra.Command {
 			// Run tests if requested
 			// Note - this runs tests strictly, e.g., returns an error if any test fails
 			if runTests {
-				testReport, err := validation.RunTests(ctx, printTestResources)
+				testReport, err := validation.RunTests(ctx, printTestResources,
+					types.ExecutionAllowed(confirmExecution),
+					types.Interactive(RunInteractively),
+				)
 				if err != nil {
 					return fmt.Errorf("error running tests")
 				}
@@ -154,12 +154,23 @@ func evaluateTarget(target oscal.EvalResult, source string, summary, machine boo
 			}
 		}
 
+		// Previously satisfied findings that could no longer be evaluated or no longer apply
+		notEvaluated := result.Collapse(map[string]result.ResultComparisonMap{
+			"no-longer-evaluated":  resultComparison["no-longer-evaluated"],
+			"no-longer-applicable": resultComparison["no-longer-applicable"],
+		})
+
 		// Print machine-readable output
-		if machine && len(resultComparison["no-longer-satisfied"]) > 0 {
-			machineOutput := result.GetMachineFriendlyObservations(resultComparison["no-longer-satisfied"])
+		if machine && (len(resultComparison["no-longer-satisfied"]) > 0 || len(notEvaluated) > 0) {
+			machineOutput := result.GetMachineFriendlyObservations(result.Collapse(map[string]result.ResultComparisonMap{
+				"no-longer-satisfied": resultComparison["no-longer-satisfied"],
+				"not-evaluated":       notEvaluated,
+			}))
 
 			jsonData, err := json.MarshalIndent(map[string]interface{}{
-				"SATISFIED_TO_NOT_SATISFIED": machineOutput[result.SATISFIED_TO_NOT_SATISFIED],
+				"SATISFIED_TO_NOT_SATISFIED":  machineOutput[result.SATISFIED_TO_NOT_SATISFIED],
+				"SATISFIED_TO_ERROR":          machineOutput[result.SATISFIED_TO_ERROR],
+				"SATISFIED_TO_NOT_APPLICABLE": machineOutput[result.SATISFIED_TO_NOT_APPLICABLE],
 			}, "", "  ")
 			if err != nil {
 				log.Fatalf("Error marshaling map to JSON: %v", err)
@@ -170,7 +181,16 @@ func evaluateTarget(target oscal.EvalResult, source string, summary, machine boo
 
 		// Check 'status' - Result if evaluation is passing or failing
 		// Fails if anything went from satisfied -> not-satisfied OR if any old findings are removed (doesn't matter whether they were satisfied or not)
+		// Findings that could no longer be evaluated or no longer apply are reported, but don't fail the evaluation
 		if status {
+			// Print findings that could not be evaluated
+			if len(notEvaluated) > 0 {
+				message.Warn("Previously satisfied finding Target-Ids that could not be evaluated or no longer apply:")
+				for id, comparison := range notEvaluated {
+					message.Warnf("%s (%s)", id, comparison.Finding.Target.Status.Reason)
+				}
+			}
+
 			// Print new-passing-findings
 			newSatisfied := resultComparison["new-satisfied"]
 			nowSatisfied := resultComparison["now-satisfied"]
@@ -182,7 +202,11 @@ func evaluateTarget(target oscal.EvalResult, source string, summary, machine boo
 				for id := range nowSatisfied {
 					message.Infof("%s", id)
 				}
+			}
 
+			// Findings that weren't evaluated would be recorded as not-satisfied in a new threshold,
+			// hiding a failure of those controls in later evaluations
+			if (len(newSatisfied) > 0 || len(nowSatisfied) > 0) && len(notEvaluated) == 0 {
 				message.Infof("New threshold identified - threshold will be updated to result %s", target.Latest.UUID)
 
 				// Update latest threshold prop
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/defenseunicorns/lula/src/cmd/common"
 	"github.com/defenseunicorns/lula/src/cmd/console"
@@ -16,11 +17,15 @@ import (
 	"github.com/defenseunicorns/lula/src/cmd/tools"
 	"github.com/defenseunicorns/lula/src/cmd/validate"
 	"github.com/defenseunicorns/lula/src/cmd/version"
+	kube "github.com/defenseunicorns/lula/src/pkg/domains/kubernetes"
 	"github.com/spf13/cobra"
 )
 
 var LogLevelCLI string
 
+// cleanupTimeout limits how long cleanup can delay shutting down on a signal
+const cleanupTimeout = 30 * time.Second
+
 var rootCmd = &cobra.Command{
 	Use: "lula",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -47,6 +52,13 @@ func Execute() {
 		case <-c:
 			fmt.Println("Got signal, shutting down...")
 			cancel()
+			// Delete any resources created in the cluster, as the deferred
+			// cleanup won't run
+			cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), cleanupTimeout)
+			if err := kube.CleanupCreated(cleanupCtx); err != nil {
+				fmt.Printf("Failed to clean up created resources, run 'lula tools cleanup --run-id %s': %v\n", kube.RunID(), err)
+			}
+			cleanupCancel()
 			os.Exit(2)
 		case <-ctx.Done():
 			return
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"fmt"
+
+	kube "github.com/defenseunicorns/lula/src/pkg/domains/kubernetes"
+	"github.com/defenseunicorns/lula/src/pkg/message"
+	"github.com/spf13/cobra"
+)
+
+var cleanupHelp = `
+To delete all resources left in the cluster by Lula runs:
+	lula tools cleanup
+
+To delete the resources left by a specific run:
+	lula tools cleanup --run-id <run id>
+
+To report the resources without deleting them:
+	lula tools cleanup --dry-run
+`
+
+type cleanupOptions struct {
+	RunID   string
+	Context string
+	DryRun  bool
+}
+
+var cleanupOpts cleanupOptions = cleanupOptions{}
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete resources left in the cluster by Lula",
+	Long: fmt.Sprintf("Find and delete the resources created by Kubernetes domain create-resources that were not cleaned up, e.g. because Lula was killed. "+
+		"Resources created by Lula are labeled %s=lula and %s=<run id>.", kube.LabelManagedBy, kube.LabelRunID),
+	Example: cleanupHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		spinner := message.NewProgressSpinner("Finding resources created by Lula")
+		defer spinner.Stop()
+
+		cluster, err := kube.GetClusterForContext(cleanupOpts.Context)
+		if err != nil {
+			message.Fatalf(err, "Failed to connect to the cluster: %s", err)
+		}
+
+		items, err := kube.CleanupOrphans(cmd.Context(), cluster, cleanupOpts.RunID, cleanupOpts.DryRun)
+		if err != nil {
+			message.WarnErr(err, "Not all resources could be searched")
+		}
+		spinner.Success()
+
+		if len(items) == 0 {
+			message.Info("No resources created by Lula were found")
+			return
+		}
+
+		header := []string{"Kind", "Namespace", "Name", "Run ID", "Status"}
+		rows := make([][]string, 0, len(items))
+		failed := 0
+		for _, item := range items {
+			status := "found"
+			switch {
+			case item.Error != nil:
+				status = fmt.Sprintf("failed: %v", item.Error)
+				failed++
+			case item.Deleted:
+				status = "deleted"
+			}
+			rows = append(rows, []string{item.Kind, item.Namespace, item.Name, item.RunID, status})
+		}
+		if err := message.Table(header, rows, []int{15, 15, 20, 25, 25}); err != nil {
+			message.WarnErr(err, "Failed to print the cleanup report")
+		}
+
+		if cleanupOpts.DryRun {
+			message.Infof("Found %d resources created by Lula", len(items))
+			return
+		}
+		if failed > 0 {
+			message.Fatalf(nil, "Failed to delete %d of %d resources created by Lula", failed, len(items))
+		}
+		message.Infof("Deleted %d resources created by Lula", len(items))
+	},
+}
+
+func init() {
+	toolsCmd.AddCommand(cleanupCmd)
+
+	cleanupCmd.Flags().StringVar(&cleanupOpts.RunID, "run-id", "", "only delete the resources created by this run")
+	cleanupCmd.Flags().StringVar(&cleanupOpts.Context, "context", "", "the kubeconfig context to use (default is the current context)")
+	cleanupCmd.Flags().BoolVar(&cleanupOpts.DryRun, "dry-run", false, "report the resources without deleting them")
+}
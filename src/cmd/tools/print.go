@@ -26,18 +26,23 @@ To print resources from lula validation manifest to output file:
 
 To print the lula validation that generated a given observation:
 	lula tools print --validation --component /path/to/component.yaml --assessment /path/to/assessment.yaml --observation-uuid <observation-uuid>
+
+To print the per-resource results of a given observation:
+	lula tools print --subjects --assessment /path/to/assessment.yaml --observation-uuid <observation-uuid>
 `
 
 var printCmdLong = `
 Prints out data about an OSCAL Observation from the OSCAL Assessment Results model. 
 Given "--resources", the command will print the JSON resources input that were provided to a Lula Validation, as identified by a given observation and assessment results file. 
 Given "--validation", the command will print the Lula Validation that generated a given observation, as identified by a given observation, assessment results file, and component definition file.
+Given "--subjects", the command will print the resources that passed or failed the Lula Validation, as identified by a given observation and assessment results file.
 `
 
 func PrintCommand() *cobra.Command {
 	var (
 		resources       bool   // -r --resources
 		validation      bool   // -v --validation
+		subjects        bool   // --subjects
 		assessment      string // -a --assessment
 		observationUuid string // -u --observation-uuid
 		outputFile      string // -o --output-file
@@ -88,6 +93,11 @@ func PrintCommand() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("error printing validation: %v", err)
 				}
+			} else if subjects {
+				err = PrintSubjects(assessment.Model, observationUuid, outputFile)
+				if err != nil {
+					return fmt.Errorf("error printing subjects: %v", err)
+				}
 			}
 			return nil
 		},
@@ -96,7 +106,8 @@ func PrintCommand() *cobra.Command {
 	// Add flags, set logic for flag behavior
 	printCmd.Flags().BoolVarP(&resources, "resources", "r", false, "true if the user is printing resources")
 	printCmd.Flags().BoolVarP(&validation, "validation", "v", false, "true if the user is printing validation")
-	printCmd.MarkFlagsMutuallyExclusive("resources", "validation")
+	printCmd.Flags().BoolVar(&subjects, "subjects", false, "true if the user is printing the per-resource results")
+	printCmd.MarkFlagsMutuallyExclusive("resources", "validation", "subjects")
 
 	printCmd.Flags().StringVarP(&assessment, "assessment", "a", "", "the path to an assessment-results file")
 	err := printCmd.MarkFlagRequired("assessment")
@@ -113,7 +124,7 @@ func PrintCommand() *cobra.Command {
 	printCmd.Flags().StringVarP(&component, "component", "c", "", "the path to a validation manifest file")
 	printCmd.MarkFlagsRequiredTogether("validation", "component")
 
-	printCmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "the path to write the resources json")
+	printCmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "the path to write the resources or subjects json")
 
 	return printCmd
 }
@@ -215,3 +226,45 @@ func PrintValidation(component *oscalTypes.ComponentDefinition, assessment *osca
 	}
 	return nil
 }
+
+func PrintSubjects(assessment *oscalTypes.AssessmentResults, observationUuid, outputFile string) error {
+	if assessment == nil {
+		return fmt.Errorf("assessment results is nil")
+	}
+
+	// Get the observation
+	observation, err := oscal.GetObservationByUuid(assessment, observationUuid)
+	if err != nil {
+		return err
+	}
+
+	resources := oscal.GetSubjectResults(observation)
+	if len(resources) == 0 {
+		return fmt.Errorf("observation does not contain any subjects")
+	}
+
+	// Print the subjects
+	if outputFile == "" {
+		header := []string{"Resource", "Kind", "Namespace", "Result", "Reason"}
+		columnSize := []int{25, 15, 15, 15, 30}
+		rows := make([][]string, 0, len(resources))
+		for _, resource := range resources {
+			result := "not-satisfied"
+			if resource.Pass {
+				result = "satisfied"
+			}
+			rows = append(rows, []string{resource.ID, resource.Kind, resource.Namespace, result, resource.Reason})
+		}
+		return message.Table(header, rows, columnSize)
+	}
+
+	jsonData, err := json.MarshalIndent(resources, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling subjects: %v", err)
+	}
+	err = os.WriteFile(outputFile, jsonData, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing subjects to file: %v", err)
+	}
+	return nil
+}
@@ -5,10 +5,12 @@ import (
 	"os"
 	"testing"
 
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/defenseunicorns/lula/src/cmd/tools"
 	"github.com/defenseunicorns/lula/src/internal/testhelpers"
+	"github.com/defenseunicorns/lula/src/pkg/common/oscal"
 	"github.com/defenseunicorns/lula/src/types"
 )
 
@@ -102,3 +104,43 @@ func TestPrintValidation(t *testing.T) {
 	})
 
 }
+
+func TestPrintSubjects(t *testing.T) {
+	t.Parallel()
+
+	resources := []types.ResourceResult{
+		{ID: "good", Kind: "Deployment", Namespace: "app", Pass: true},
+		{ID: "bad", Kind: "Deployment", Namespace: "app", Pass: false, Reason: "missing resource limits"},
+	}
+	assessment := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{
+				Observations: &[]oscalTypes.Observation{
+					{UUID: "with-subjects", Subjects: oscal.CreateSubjects(resources)},
+					{UUID: "without-subjects"},
+				},
+			},
+		},
+	}
+
+	t.Run("Test print subjects", func(t *testing.T) {
+		tmpFile := testhelpers.CreateTempFile(t, ".json")
+		defer os.Remove(tmpFile.Name())
+
+		err := tools.PrintSubjects(assessment, "with-subjects", tmpFile.Name())
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(tmpFile.Name())
+		require.NoError(t, err)
+
+		var printed []types.ResourceResult
+		err = json.Unmarshal(data, &printed)
+		require.NoError(t, err)
+		require.Equal(t, resources, printed)
+	})
+
+	t.Run("Test print subjects with no subjects", func(t *testing.T) {
+		err := tools.PrintSubjects(assessment, "without-subjects", "")
+		require.ErrorContains(t, err, "observation does not contain any subjects")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -29,6 +30,10 @@ To run validations non-interactively (no execution)
 	lula dev validate -f ./oscal-component.yaml --non-interactive
 To run validations and their tests, generating a test-results file
 	lula dev validate -f ./oscal-component.yaml --run-tests
+To run up to 8 validations in parallel
+	lula validate -f ./oscal-component.yaml --concurrency 8
+To stop any validation that takes longer than 5 minutes
+	lula validate -f ./oscal-component.yaml --timeout 5m
 `
 
 var (
@@ -51,6 +56,8 @@ func ValidateCommand() *cobra.Command {
 		runNonInteractively bool
 		saveResources       bool
 		runTests            bool
+		concurrency         int
+		timeout             time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -88,6 +95,8 @@ func ValidateCommand() *cobra.Command {
 				validation.WithSaveResources(saveResources),
 				validation.WithAllowExecution(confirmExecution, runNonInteractively),
 				validation.WithTests(runTests),
+				validation.WithConcurrency(concurrency),
+				validation.WithTimeout(timeout),
 			)
 			if err != nil {
 				return fmt.Errorf("error creating new validator: %v", err)
@@ -124,6 +133,8 @@ func ValidateCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&runNonInteractively, "non-interactive", false, "run the command non-interactively")
 	cmd.Flags().BoolVar(&saveResources, "save-resources", false, "saves the resources to 'resources' directory at assessment-results level")
 	cmd.Flags().BoolVar(&runTests, "run-tests", false, "run tests specified in the validation, writes to test-results-<timestamp>.yaml in output directory")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "the maximum number of validations to run in parallel; executable validations are always run one at a time")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "the default maximum duration of each validation, e.g. 5m; validations can set their own in metadata.timeout (default no timeout)")
 	cmd.Flags().StringSliceVarP(&setOpts, "set", "s", []string{}, "set a value in the template data")
 
 	return cmd
@@ -17,9 +17,12 @@ import (
 
 var (
 	satisfiedColors = map[string]lipgloss.Style{
-		"satisfied":     lipgloss.NewStyle().Foreground(lipgloss.Color("#3ad33c")),
-		"not-satisfied": lipgloss.NewStyle().Foreground(lipgloss.Color("#e36750")),
-		"other":         lipgloss.NewStyle().Foreground(lipgloss.Color("#f3f3f3")),
+		"satisfied":      lipgloss.NewStyle().Foreground(lipgloss.Color("#3ad33c")),
+		"not-satisfied":  lipgloss.NewStyle().Foreground(lipgloss.Color("#e36750")),
+		"error":          lipgloss.NewStyle().Foreground(lipgloss.Color("#f2c14e")),
+		"timed-out":      lipgloss.NewStyle().Foreground(lipgloss.Color("#f2c14e")),
+		"not-applicable": lipgloss.NewStyle().Foreground(lipgloss.Color("#9e9e9e")),
+		"other":          lipgloss.NewStyle().Foreground(lipgloss.Color("#f3f3f3")),
 	}
 )
 
@@ -99,12 +102,10 @@ func GetResults(assessmentResults *oscalTypes.AssessmentResults) []result {
 				state := "undefined"
 				var remarks strings.Builder
 				if o.RelevantEvidence != nil {
+					if observationState := pkgResult.ObservationState(o.RelevantEvidence); observationState != "" {
+						state = observationState
+					}
 					for _, e := range *o.RelevantEvidence {
-						if e.Description == "Result: satisfied\n" {
-							state = "satisfied"
-						} else if e.Description == "Result: not-satisfied\n" {
-							state = "not-satisfied"
-						}
 						if e.Remarks != "" {
 							remarks.WriteString(strings.ReplaceAll(e.Remarks, "\n", " "))
 						}
@@ -182,29 +182,12 @@ func getValidationText(validationLink validationLink) string {
 
 	if validation.Domain != nil {
 		text.WriteString(fmt.Sprintf("Domain: %s\n", important.Render(validation.Domain.Type)))
-		switch validation.Domain.Type {
-		case "kubernetes":
-			kubeSpec, err := common.ToYamlString(validation.Domain.KubernetesSpec)
-			if err != nil {
-				common.PrintToLog("error converting kubeSpec to yaml: %v", err)
-				kubeSpec = ""
-			}
-			text.WriteString(kubeSpec)
-		case "api":
-			apiSpec, err := common.ToYamlString(validation.Domain.ApiSpec)
-			if err != nil {
-				common.PrintToLog("error converting apiSpec to yaml: %v", err)
-				apiSpec = ""
-			}
-			text.WriteString(apiSpec)
-		case "file":
-			fileSpec, err := common.ToYamlString(validation.Domain.FileSpec)
-			if err != nil {
-				common.PrintToLog("error converting fileSpec to yaml: %v", err)
-				fileSpec = ""
-			}
-			text.WriteString(fileSpec)
-		}
+		text.WriteString(domainSpec(validation.Domain))
+		text.WriteString("\n\n")
+	}
+	for _, domain := range validation.Domains {
+		text.WriteString(fmt.Sprintf("Domain %s: %s\n", domain.Name, important.Render(domain.Type)))
+		text.WriteString(domainSpec(&domain.Domain))
 		text.WriteString("\n\n")
 	}
 
@@ -230,3 +213,24 @@ func getValidationText(validationLink validationLink) string {
 
 	return text.String()
 }
+
+// domainSpec returns the spec of the domain as yaml
+func domainSpec(domain *pkgcommon.Domain) string {
+	var spec interface{}
+	switch domain.Type {
+	case "kubernetes":
+		spec = domain.KubernetesSpec
+	case "api":
+		spec = domain.ApiSpec
+	case "file":
+		spec = domain.FileSpec
+	default:
+		return ""
+	}
+	specYaml, err := common.ToYamlString(spec)
+	if err != nil {
+		common.PrintToLog("error converting %s spec to yaml: %v", domain.Type, err)
+		return ""
+	}
+	return specYaml
+}
@@ -186,7 +186,7 @@ func (m *ValidateModel) setInitialContent() {
 			content.WriteString("Run Validations?\n\n")
 			content.WriteString("🔍 Validate Component Definition on Target: ")
 			content.WriteString(m.target)
-			requirementStore.ResolveLulaValidations(m.validationStore)
+			requirementStore.ResolveLulaValidations(context.Background(), m.validationStore)
 			reqtStats := requirementStore.GetStats(m.validationStore)
 			content.WriteString(fmt.Sprintf("\n\n• Found %d Implemented Requirements", reqtStats.TotalRequirements))
 			content.WriteString(fmt.Sprintf("\n• Found %d runnable Lula Validations", reqtStats.TotalValidations))
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
@@ -16,9 +17,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/defenseunicorns/lula/src/pkg/domains/api"
+	"github.com/defenseunicorns/lula/src/pkg/domains/command"
 	"github.com/defenseunicorns/lula/src/pkg/domains/files"
 	kube "github.com/defenseunicorns/lula/src/pkg/domains/kubernetes"
 	"github.com/defenseunicorns/lula/src/pkg/message"
+	"github.com/defenseunicorns/lula/src/pkg/providers/cel"
 	"github.com/defenseunicorns/lula/src/pkg/providers/kyverno"
 	"github.com/defenseunicorns/lula/src/pkg/providers/opa"
 	"github.com/defenseunicorns/lula/src/types"
@@ -154,11 +157,64 @@ func GetDomain(domain *Domain) (types.Domain, error) {
 		return api.CreateApiDomain(domain.ApiSpec)
 	case "file":
 		return files.CreateDomain(domain.FileSpec)
+	case "command":
+		return command.CreateDomain(domain.CommandSpec)
 	default:
 		return nil, fmt.Errorf("domain is unsupported")
 	}
 }
 
+// GetDomains returns a domain combining the named domains of a cross-domain
+// validation. The resources of each domain are keyed by the domain name.
+func GetDomains(domains []NamedDomain) (types.Domain, error) {
+	combined := &crossDomain{}
+	for _, named := range domains {
+		if named.Name == "" {
+			return nil, fmt.Errorf("domain name cannot be empty")
+		}
+		if slices.Contains(combined.Names, named.Name) {
+			return nil, fmt.Errorf("domain name %s is not unique", named.Name)
+		}
+		domain, err := GetDomain(&named.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("domain %s: %w", named.Name, err)
+		}
+		combined.Names = append(combined.Names, named.Name)
+		combined.Domains = append(combined.Domains, domain)
+	}
+	return combined, nil
+}
+
+// crossDomain gets the resources of several domains. Its fields are exported
+// so the domain specs are part of its resource cache key.
+type crossDomain struct {
+	Names   []string       `json:"names"`
+	Domains []types.Domain `json:"domains"`
+}
+
+// GetResources returns the resources of every domain, keyed by domain name
+func (c *crossDomain) GetResources(ctx context.Context) (types.DomainResources, error) {
+	resources := make(types.DomainResources, len(c.Domains))
+	for i, domain := range c.Domains {
+		domainResources, err := types.GetDomainResources(ctx, domain)
+		if err != nil {
+			return nil, fmt.Errorf("domain %s: %w", c.Names[i], err)
+		}
+		resources[c.Names[i]] = map[string]interface{}(domainResources)
+	}
+	return resources, nil
+}
+
+// IsExecutable returns true if any of the domains is executable
+func (c *crossDomain) IsExecutable() bool {
+	for _, domain := range c.Domains {
+		if domain.IsExecutable() {
+			return true
+		}
+	}
+	return false
+}
+
 func GetProvider(provider *Provider, ctx context.Context) (types.Provider, error) {
 	if provider == nil {
 		return nil, fmt.Errorf("provider is nil")
@@ -168,13 +224,15 @@ func GetProvider(provider *Provider, ctx context.Context) (types.Provider, error
 		return opa.CreateOpaProvider(ctx, provider.OpaSpec)
 	case "kyverno":
 		return kyverno.CreateKyvernoProvider(ctx, provider.KyvernoSpec)
+	case "cel":
+		return cel.CreateCelProvider(ctx, provider.CelSpec)
 	default:
 		return nil, fmt.Errorf("provider is unsupported")
 	}
 }
 
 // Converts a raw string to a Validation object (string -> common.Validation -> types.Validation)
-func ValidationFromString(raw, uuid string) (validation types.LulaValidation, err error) {
+func ValidationFromString(ctx context.Context, raw, uuid string) (validation types.LulaValidation, err error) {
 	if raw == "" {
 		return validation, fmt.Errorf("validation string is empty")
 	}
@@ -185,7 +243,7 @@ func ValidationFromString(raw, uuid string) (validation types.LulaValidation, er
 		return validation, err
 	}
 
-	validation, err = validationData.ToLulaValidation(uuid)
+	validation, err = validationData.ToLulaValidation(ctx, uuid)
 	if err != nil {
 		return validation, err
 	}
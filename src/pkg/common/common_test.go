@@ -13,9 +13,12 @@ import (
 
 	"github.com/defenseunicorns/lula/src/pkg/common"
 	"github.com/defenseunicorns/lula/src/pkg/domains/api"
+	"github.com/defenseunicorns/lula/src/pkg/domains/command"
 	kube "github.com/defenseunicorns/lula/src/pkg/domains/kubernetes"
+	"github.com/defenseunicorns/lula/src/pkg/providers/cel"
 	"github.com/defenseunicorns/lula/src/pkg/providers/kyverno"
 	"github.com/defenseunicorns/lula/src/pkg/providers/opa"
+	"github.com/defenseunicorns/lula/src/types"
 )
 
 const multiValidationPath = "../../test/e2e/scenarios/remote-validations/multi-validations.yaml"
@@ -109,6 +112,37 @@ func TestGetDomain(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			name: "valid command domain",
+			domain: common.Domain{
+				Type: "command",
+				CommandSpec: &command.Spec{
+					Commands: []command.Command{
+						{
+							Name:    "version",
+							Command: "lula",
+							Args:    []string{"version"},
+						},
+					},
+				},
+			},
+			expectedErr:    false,
+			expectedDomain: "command.Domain",
+		},
+		{
+			name: "invalid command domain",
+			domain: common.Domain{
+				Type: "command",
+				CommandSpec: &command.Spec{
+					Commands: []command.Command{
+						{
+							Name: "version",
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
 		{
 			name: "invalid type domain",
 			domain: common.Domain{
@@ -134,6 +168,10 @@ func TestGetDomain(t *testing.T) {
 				if _, ok := result.(api.ApiDomain); !ok {
 					t.Errorf("Expected result to be api.ApiDomain, got %T", result)
 				}
+			case "command.Domain":
+				if _, ok := result.(command.Domain); !ok {
+					t.Errorf("Expected result to be command.Domain, got %T", result)
+				}
 			case "nil":
 				if result != nil {
 					t.Errorf("Expected result to be nil, got %T", result)
@@ -143,6 +181,55 @@ func TestGetDomain(t *testing.T) {
 	}
 }
 
+func TestGetDomains(t *testing.T) {
+	t.Parallel()
+
+	cluster := common.NamedDomain{
+		Name: "cluster",
+		Domain: common.Domain{
+			Type:           "kubernetes",
+			KubernetesSpec: &kube.KubernetesSpec{Resources: []kube.Resource{}},
+		},
+	}
+	host := common.NamedDomain{
+		Name: "host",
+		Domain: common.Domain{
+			Type: "command",
+			CommandSpec: &command.Spec{
+				Commands: []command.Command{{Name: "greeting", Command: "echo", Args: []string{"hello"}}},
+			},
+		},
+	}
+
+	t.Run("resources keyed by domain name", func(t *testing.T) {
+		domain, err := common.GetDomains([]common.NamedDomain{cluster, host})
+		require.NoError(t, err)
+		require.True(t, domain.IsExecutable())
+
+		resources, err := domain.GetResources(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{}, resources["cluster"])
+		greeting := resources["host"].(map[string]interface{})["greeting"].(types.DomainResources)
+		require.Equal(t, "hello\n", greeting["stdout"])
+	})
+
+	t.Run("not executable", func(t *testing.T) {
+		domain, err := common.GetDomains([]common.NamedDomain{cluster})
+		require.NoError(t, err)
+		require.False(t, domain.IsExecutable())
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := common.GetDomains([]common.NamedDomain{cluster, cluster})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid domain", func(t *testing.T) {
+		_, err := common.GetDomains([]common.NamedDomain{{Name: "foo", Domain: common.Domain{Type: "foo"}}})
+		require.Error(t, err)
+	})
+}
+
 func TestGetProvider(t *testing.T) {
 	t.Parallel()
 
@@ -192,6 +279,25 @@ func TestGetProvider(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			name: "valid cel provider",
+			provider: common.Provider{
+				Type: "cel",
+				CelSpec: &cel.CelSpec{
+					Validation: "size(resources) > 0",
+				},
+			},
+			expectedErr:      false,
+			expectedProvider: "cel.CelProvider",
+		},
+		{
+			name: "invalid cel provider",
+			provider: common.Provider{
+				Type:    "cel",
+				CelSpec: &cel.CelSpec{},
+			},
+			expectedErr: true,
+		},
 		{
 			name: "invalid type provider",
 			provider: common.Provider{
@@ -219,6 +325,10 @@ func TestGetProvider(t *testing.T) {
 				if _, ok := result.(kyverno.KyvernoProvider); !ok {
 					t.Errorf("Expected result to be kyverno.KyvernoProvider, got %T", result)
 				}
+			case "cel.CelProvider":
+				if _, ok := result.(cel.CelProvider); !ok {
+					t.Errorf("Expected result to be cel.CelProvider, got %T", result)
+				}
 			case "nil":
 				if result != nil {
 					t.Errorf("Expected result to be nil, got %T", result)
@@ -269,7 +379,7 @@ func TestValidationFromString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lulaValidation, err := common.ValidationFromString(tt.data, tt.uuid)
+			lulaValidation, err := common.ValidationFromString(context.Background(), tt.data, tt.uuid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidationFromString() error = %v, wantErr %v", err, tt.wantErr)
 				return
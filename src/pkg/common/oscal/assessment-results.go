@@ -1,6 +1,7 @@
 package oscal
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -149,7 +150,8 @@ func EvaluateResults(thresholdResult *oscalTypes.Result, newResult *oscalTypes.R
 	// Compare threshold result to new result and vice versa
 	comparedToThreshold := result.NewResultComparisonMap(*newResult, *thresholdResult)
 
-	// Group by categories
+	// Group by categories, findings that could no longer be evaluated or no longer apply are
+	// reported but don't fail the evaluation
 	categories := []struct {
 		name        string
 		stateChange result.StateChange
@@ -174,6 +176,18 @@ func EvaluateResults(thresholdResult *oscalTypes.Result, newResult *oscalTypes.R
 			satisfied:   false,
 			status:      false,
 		},
+		{
+			name:        "no-longer-evaluated",
+			stateChange: result.SATISFIED_TO_ERROR,
+			satisfied:   false,
+			status:      true,
+		},
+		{
+			name:        "no-longer-applicable",
+			stateChange: result.SATISFIED_TO_NOT_APPLICABLE,
+			satisfied:   false,
+			status:      true,
+		},
 		{
 			name:        "now-satisfied",
 			stateChange: result.NOT_SATISFIED_TO_SATISFIED,
@@ -359,6 +373,9 @@ func CreateObservation(method string, relevantEvidence *[]oscalTypes.RelevantEvi
 			},
 		}
 	}
+	if validation != nil && validation.Result != nil && len(validation.Result.Resources) > 0 {
+		observation.Subjects = CreateSubjects(validation.Result.Resources)
+	}
 	if resourcesHref != "" {
 		observation.Links = &[]oscalTypes.Link{
 			{
@@ -370,6 +387,98 @@ func CreateObservation(method string, relevantEvidence *[]oscalTypes.RelevantEvi
 	return observation
 }
 
+// CreateRelevantEvidence creates the relevant evidence of a validation result, observations are rendered
+// into the remarks and structured (non-string) observations are also kept as JSON in props
+func CreateRelevantEvidence(state string, observations map[string]interface{}) *[]oscalTypes.RelevantEvidence {
+	relevantEvidence := oscalTypes.RelevantEvidence{
+		Description: fmt.Sprintf("Result: %s\n", state),
+		Remarks:     types.FormatObservations(observations),
+	}
+
+	keys := make([]string, 0, len(observations))
+	for k := range observations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := make([]oscalTypes.Property, 0)
+	for _, k := range keys {
+		if _, ok := observations[k].(string); ok {
+			continue
+		}
+		data, err := json.Marshal(observations[k])
+		if err != nil {
+			continue
+		}
+		props = append(props, oscalTypes.Property{
+			Name:    "observation",
+			Ns:      LULA_NAMESPACE,
+			Value:   string(data),
+			Remarks: k,
+		})
+	}
+	if len(props) > 0 {
+		relevantEvidence.Props = &props
+	}
+
+	return &[]oscalTypes.RelevantEvidence{relevantEvidence}
+}
+
+// CreateSubjects creates the observation subjects from the per-resource results of a validation, the
+// subject UUIDs are derived from the resources so the same resource has the same UUID across results
+func CreateSubjects(resources []types.ResourceResult) *[]oscalTypes.SubjectReference {
+	subjects := make([]oscalTypes.SubjectReference, 0, len(resources))
+	for _, resource := range resources {
+		state := "not-satisfied"
+		if resource.Pass {
+			state = "satisfied"
+		}
+		props := []oscalTypes.Property{
+			{
+				Name:  "result",
+				Ns:    LULA_NAMESPACE,
+				Value: state,
+			},
+		}
+		if resource.Kind != "" {
+			props = append(props, oscalTypes.Property{Name: "kind", Ns: LULA_NAMESPACE, Value: resource.Kind})
+		}
+		if resource.Namespace != "" {
+			props = append(props, oscalTypes.Property{Name: "namespace", Ns: LULA_NAMESPACE, Value: resource.Namespace})
+		}
+		subjects = append(subjects, oscalTypes.SubjectReference{
+			SubjectUuid: uuid.NewUUIDWithSource(fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Namespace, resource.ID)),
+			Type:        "resource",
+			Title:       resource.ID,
+			Props:       &props,
+			Remarks:     resource.Reason,
+		})
+	}
+	return &subjects
+}
+
+// GetSubjectResults returns the per-resource results recorded in the subjects of an observation
+func GetSubjectResults(observation *oscalTypes.Observation) []types.ResourceResult {
+	if observation == nil || observation.Subjects == nil {
+		return nil
+	}
+
+	resources := make([]types.ResourceResult, 0, len(*observation.Subjects))
+	for _, subject := range *observation.Subjects {
+		_, state := GetProp("result", LULA_NAMESPACE, subject.Props)
+		_, kind := GetProp("kind", LULA_NAMESPACE, subject.Props)
+		_, namespace := GetProp("namespace", LULA_NAMESPACE, subject.Props)
+		resources = append(resources, types.ResourceResult{
+			ID:        subject.Title,
+			Kind:      kind,
+			Namespace: namespace,
+			Pass:      state == "satisfied",
+			Reason:    subject.Remarks,
+		})
+	}
+	return resources
+}
+
 // Creates a result from findings and observations
 func CreateResult(findingMap map[string]oscalTypes.Finding, observations []oscalTypes.Observation) (oscalTypes.Result, error) {
 
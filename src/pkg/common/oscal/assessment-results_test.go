@@ -427,6 +427,83 @@ func TestEvaluateResultsFailed(t *testing.T) {
 
 }
 
+func TestEvaluateResultsErrored(t *testing.T) {
+	message.NoProgress = true
+	mockThresholdResult := oscalTypes.Result{
+		Findings: &[]oscalTypes.Finding{
+			findingMapPass["ID-1"],
+		},
+	}
+
+	erroredFinding := findingMapFail["ID-1"]
+	erroredFinding.Target.Status.Reason = "error"
+	mockEvaluationResult := oscalTypes.Result{
+		Findings: &[]oscalTypes.Finding{
+			erroredFinding,
+		},
+	}
+
+	status, findings, err := oscal.EvaluateResults(&mockThresholdResult, &mockEvaluationResult)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A finding that could not be evaluated is reported, but doesn't fail the threshold
+	if !status {
+		t.Fatal("error - evaluation failed for a finding that could not be evaluated")
+	}
+
+	if len(findings["no-longer-satisfied"]) != 0 {
+		t.Fatal("error - expected 0 failed findings, got ", len(findings["no-longer-satisfied"]))
+	}
+
+	if len(findings["no-longer-evaluated"]) != 1 {
+		t.Fatal("error - expected 1 errored finding, got ", len(findings["no-longer-evaluated"]))
+	}
+}
+
+func TestEvaluateResultsNotApplicable(t *testing.T) {
+	message.NoProgress = true
+	notApplicableFinding := findingMapFail["ID-1"]
+	notApplicableFinding.Target.Status.Reason = "not-applicable"
+
+	t.Run("previously satisfied", func(t *testing.T) {
+		status, findings, err := oscal.EvaluateResults(
+			&oscalTypes.Result{Findings: &[]oscalTypes.Finding{findingMapPass["ID-1"]}},
+			&oscalTypes.Result{Findings: &[]oscalTypes.Finding{notApplicableFinding}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !status {
+			t.Fatal("error - evaluation failed for a finding that no longer applies")
+		}
+		if len(findings["no-longer-applicable"]) != 1 {
+			t.Fatal("error - expected 1 not-applicable finding, got ", len(findings["no-longer-applicable"]))
+		}
+	})
+
+	t.Run("previously not-satisfied", func(t *testing.T) {
+		status, findings, err := oscal.EvaluateResults(
+			&oscalTypes.Result{Findings: &[]oscalTypes.Finding{findingMapFail["ID-1"]}},
+			&oscalTypes.Result{Findings: &[]oscalTypes.Finding{notApplicableFinding}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !status {
+			t.Fatal("error - evaluation failed")
+		}
+		// A finding that doesn't apply doesn't make a failing control satisfied
+		if len(findings["now-satisfied"]) != 0 {
+			t.Fatal("error - expected 0 now-satisfied findings, got ", len(findings["now-satisfied"]))
+		}
+		if len(findings["unchanged-not-satisfied"]) != 1 {
+			t.Fatal("error - expected 1 unchanged-not-satisfied finding, got ", len(findings["unchanged-not-satisfied"]))
+		}
+	})
+}
+
 func TestEvaluateResultsNoFindings(t *testing.T) {
 	message.NoProgress = true
 	mockThresholdResult := oscalTypes.Result{
@@ -730,3 +807,30 @@ func FuzzNewAssessmentResults(f *testing.F) {
 		assessment.NewModel(a)
 	})
 }
+
+func TestCreateRelevantEvidence(t *testing.T) {
+	t.Parallel()
+
+	relevantEvidence := oscal.CreateRelevantEvidence("not-satisfied", map[string]interface{}{
+		"validate.msg":       "pod-a is missing a label",
+		"validate.offenders": []interface{}{"pod-a"},
+	})
+	require.Len(t, *relevantEvidence, 1)
+
+	evidence := (*relevantEvidence)[0]
+	assert.Equal(t, "Result: not-satisfied\n", evidence.Description)
+	assert.Equal(t, "validate.msg: pod-a is missing a label\nvalidate.offenders:\n  - pod-a\n", evidence.Remarks)
+	require.NotNil(t, evidence.Props)
+	assert.Equal(t, []oscalTypes.Property{
+		{
+			Name:    "observation",
+			Ns:      oscal.LULA_NAMESPACE,
+			Value:   `["pod-a"]`,
+			Remarks: "validate.offenders",
+		},
+	}, *evidence.Props)
+
+	relevantEvidence = oscal.CreateRelevantEvidence("satisfied", nil)
+	assert.Empty(t, (*relevantEvidence)[0].Remarks)
+	assert.Nil(t, (*relevantEvidence)[0].Props)
+}
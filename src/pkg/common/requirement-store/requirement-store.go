@@ -1,12 +1,14 @@
 package requirementstore
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/defenseunicorns/lula/src/pkg/common"
 	"github.com/defenseunicorns/lula/src/pkg/common/oscal"
+	"github.com/defenseunicorns/lula/src/pkg/common/result"
 	validationstore "github.com/defenseunicorns/lula/src/pkg/common/validation-store"
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	"github.com/defenseunicorns/lula/src/types"
@@ -34,19 +36,20 @@ func NewRequirementStore(controlImplementations *[]oscalTypes.ControlImplementat
 }
 
 // ResolveLulaValidations resolves the linked Lula validations with the requirements and populates the ValidationStore.validationMap
-func (r *RequirementStore) ResolveLulaValidations(validationStore *validationstore.ValidationStore) {
+func (r *RequirementStore) ResolveLulaValidations(ctx context.Context, validationStore *validationstore.ValidationStore) {
 	// get all Lula validations linked to the requirement
 	var lulaValidation *types.LulaValidation
 	for _, requirement := range r.requirementMap {
 		if requirement.ImplementedRequirement.Links != nil {
 			for _, link := range *requirement.ImplementedRequirement.Links {
 				if common.IsLulaLink(link) {
-					_, err := validationStore.GetLulaValidation(link.Href)
+					_, err := validationStore.GetLulaValidation(ctx, link.Href)
 					if err != nil {
 						message.Debugf("Error adding validation from link %s: %v", link.Href, err)
 						// Create new LulaValidation and add to validationStore
 						lulaValidation = types.CreateFailingLulaValidation("lula-validation-error")
-						lulaValidation.Result.Observations = map[string]string{
+						lulaValidation.Result.State = "error"
+						lulaValidation.Result.Observations = map[string]interface{}{
 							fmt.Sprintf("Error getting Lula validation %s", link.Href): err.Error(),
 						}
 						validationStore.AddLulaValidation(lulaValidation, link.Href)
@@ -63,7 +66,7 @@ func (r *RequirementStore) GenerateFindings(validationStore *validationstore.Val
 	for _, requirement := range r.requirementMap {
 		// This should produce a finding - check if an existing finding for the control-id has been processed
 		var finding oscalTypes.Finding
-		var pass, fail int
+		var pass, fail, errored, notApplicable int
 
 		// A single finding should be "control-id centric"
 		if _, ok := r.findingMap[requirement.ImplementedRequirement.ControlId]; ok {
@@ -80,11 +83,16 @@ func (r *RequirementStore) GenerateFindings(validationStore *validationstore.Val
 		if requirement.ImplementedRequirement.Links != nil {
 			relatedObservations := make([]oscalTypes.RelatedObservation, 0, len(*requirement.ImplementedRequirement.Links))
 			for _, link := range *requirement.ImplementedRequirement.Links {
-				observation, passBool := validationStore.GetRelatedObservation(link.Href)
+				observation, state := validationStore.GetRelatedObservationState(link.Href)
 				relatedObservations = append(relatedObservations, observation)
-				if passBool {
+				switch {
+				case state == "satisfied":
 					pass++
-				} else {
+				case state == "not-applicable":
+					notApplicable++
+				case result.IsErrorState(state):
+					errored++
+				default:
 					fail++
 				}
 			}
@@ -95,31 +103,56 @@ func (r *RequirementStore) GenerateFindings(validationStore *validationstore.Val
 			finding.RelatedObservations = &relatedObservations
 		}
 
-		// Using language from Assessment Results model for Target Objective Status State
+		// Carry over the outcome of requirements already processed for the control, a requirement
+		// without validations leaves the control not-satisfied
+		existing := finding.Target.Status
+		switch existing.Reason {
+		case "pass":
+			pass++
+		case "fail":
+			fail++
+		case "error":
+			errored++
+		case "not-applicable":
+			notApplicable++
+		case "other":
+			if pass+fail+errored+notApplicable > 0 {
+				fail++
+			}
+		}
+
+		// Using language from Assessment Results model for Target Objective Status State, validations
+		// that could not be evaluated or do not apply are reported with their own reason rather than as failures
 		var state, reason, remarks string
-		message.Debugf("Pass: %v / Fail: %v / Existing State: %s", pass, fail, finding.Target.Status.State)
-		if finding.Target.Status.State == "not-satisfied" {
+		message.Debugf("Pass: %v / Fail: %v / Error: %v / Not Applicable: %v / Existing State: %s", pass, fail, errored, notApplicable, existing.State)
+		switch {
+		case fail > 0:
 			state = "not-satisfied"
+			reason = "fail"
 			// If the previous state was not-satisfied but there are RelatedObservations
-			// Then we want to update the reason or remarks in the event the reason
-			// was 'other' previously
-			if finding.RelatedObservations != nil {
-				reason = "fail"
+			// Then we want to update the remarks in the event the reason was 'other' previously
+			if existing.State == "not-satisfied" && finding.RelatedObservations != nil {
 				remarks = "One or more Lula validations are failing"
 			}
-		} else if pass > 0 && fail == 0 {
+		case errored > 0:
+			state = "not-satisfied"
+			reason = "error"
+			remarks = "One or more Lula validations could not be evaluated"
+		case pass > 0:
 			state = "satisfied"
 			reason = "pass"
-		} else if pass == 0 && fail == 0 {
-			// If there is no result (pass or fail) it means that no validation was performed by Lula.
+		case notApplicable > 0:
+			// Not reported as satisfied, so a control that doesn't apply can't be counted as passing
+			state = "not-satisfied"
+			reason = "not-applicable"
+			remarks = "No Lula validations were applicable to this control"
+		default:
+			// If there is no result it means that no validation was performed by Lula.
 			// When that happens we can explicitly add a note to the finding, to properly explain the
 			// reason for the control being not-satisfied
 			state = "not-satisfied"
 			reason = "other"
 			remarks = "No Lula validations were defined for this control"
-		} else {
-			state = "not-satisfied"
-			reason = "fail"
 		}
 
 		finding.Target = oscalTypes.FindingTarget{
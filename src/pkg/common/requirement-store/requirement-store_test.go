@@ -1,15 +1,19 @@
 package requirementstore_test
 
 import (
+	"context"
 	"testing"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/defenseunicorns/lula/src/internal/testhelpers"
+	"github.com/defenseunicorns/lula/src/pkg/common"
 	"github.com/defenseunicorns/lula/src/pkg/common/oscal"
 	requirementstore "github.com/defenseunicorns/lula/src/pkg/common/requirement-store"
 	validationstore "github.com/defenseunicorns/lula/src/pkg/common/validation-store"
+	"github.com/defenseunicorns/lula/src/pkg/message"
+	"github.com/defenseunicorns/lula/src/types"
 )
 
 const (
@@ -44,3 +48,57 @@ func TestGenerateFindings(t *testing.T) {
 	assert.Equal(t, "other", findings["ID-2"].Target.Status.Reason)
 	assert.Equal(t, "No Lula validations were defined for this control", findings["ID-2"].Target.Status.Remarks)
 }
+
+func TestGenerateFindingsStates(t *testing.T) {
+	message.NoProgress = true
+	vs := validationstore.NewValidationStore()
+	passing := types.CreatePassingLulaValidation("passing")
+	failing := types.CreateFailingLulaValidation("failing")
+	errored := types.CreateFailingLulaValidation("errored")
+	errored.Result.State = "error"
+	notApplicable := types.CreatePassingLulaValidation("not-applicable")
+	notApplicable.Result.State = "not-applicable"
+	vs.AddLulaValidation(passing, "passing")
+	vs.AddLulaValidation(failing, "failing")
+	vs.AddLulaValidation(errored, "errored")
+	vs.AddLulaValidation(notApplicable, "not-applicable")
+	vs.RunValidations(context.Background(), true, false, "", 1, 0)
+
+	requirement := func(controlId string, validations ...string) oscalTypes.ImplementedRequirementControlImplementation {
+		links := make([]oscalTypes.Link, 0, len(validations))
+		for _, v := range validations {
+			links = append(links, oscalTypes.Link{Href: common.AddIdPrefix(v), Rel: "lula", Text: "Lula Validation"})
+		}
+		return oscalTypes.ImplementedRequirementControlImplementation{
+			UUID:      controlId,
+			ControlId: controlId,
+			Links:     &links,
+		}
+	}
+	impls := []oscalTypes.ControlImplementationSet{
+		{
+			ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+				requirement("fail", "passing", "errored", "failing"),
+				requirement("error", "passing", "errored", "not-applicable"),
+				requirement("pass", "passing", "not-applicable"),
+				requirement("not-applicable", "not-applicable"),
+			},
+		},
+	}
+	rs := requirementstore.NewRequirementStore(&impls)
+
+	findings := rs.GenerateFindings(vs)
+
+	tests := []struct {
+		controlId, state, reason string
+	}{
+		{"fail", "not-satisfied", "fail"},
+		{"error", "not-satisfied", "error"},
+		{"pass", "satisfied", "pass"},
+		{"not-applicable", "not-satisfied", "not-applicable"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.state, findings[tt.controlId].Target.Status.State, tt.controlId)
+		assert.Equal(t, tt.reason, findings[tt.controlId].Target.Status.Reason, tt.controlId)
+	}
+}
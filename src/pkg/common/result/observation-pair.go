@@ -121,6 +121,11 @@ func compareRelevantEvidence(relevantEvidence *[]oscalTypes.RelevantEvidence, co
 		state = NOT_SATISFIED_TO_SATISFIED
 	} else if !reResults && compReResults {
 		state = SATISFIED_TO_NOT_SATISFIED
+		if observationState := ObservationState(relevantEvidence); IsErrorState(observationState) {
+			state = SATISFIED_TO_ERROR
+		} else if observationState == "not-applicable" {
+			state = SATISFIED_TO_NOT_APPLICABLE
+		}
 	}
 
 	return state
@@ -130,7 +135,7 @@ func getObservationResult(relevantEvidence *[]oscalTypes.RelevantEvidence) bool
 	var satisfied bool
 	if relevantEvidence != nil {
 		for _, re := range *relevantEvidence {
-			if !strings.Contains(re.Description, "not-satisfied") {
+			if strings.TrimSpace(re.Description) == "Result: satisfied" {
 				satisfied = true
 			}
 		}
@@ -138,6 +143,30 @@ func getObservationResult(relevantEvidence *[]oscalTypes.RelevantEvidence) bool
 	return satisfied
 }
 
+// ObservationState returns the result state recorded in the relevant evidence of an observation,
+// i.e., the first state that is not satisfied, or empty if no result is recorded
+func ObservationState(relevantEvidence *[]oscalTypes.RelevantEvidence) string {
+	var state string
+	if relevantEvidence != nil {
+		for _, re := range *relevantEvidence {
+			description := strings.TrimSpace(re.Description)
+			if !strings.HasPrefix(description, "Result: ") {
+				continue
+			}
+			state = strings.TrimPrefix(description, "Result: ")
+			if state != "satisfied" {
+				break
+			}
+		}
+	}
+	return state
+}
+
+// IsErrorState returns true if the state is that of a validation that could not be evaluated
+func IsErrorState(state string) bool {
+	return state == "error" || state == "timed-out"
+}
+
 func getRemarks(relevantEvidence *[]oscalTypes.RelevantEvidence) string {
 	var remarks string
 	if relevantEvidence != nil {
@@ -11,11 +11,13 @@ import (
 type StateChange string
 
 const (
-	NOT_SATISFIED_TO_SATISFIED StateChange = "NOT SATISFIED TO SATISFIED"
-	SATISFIED_TO_NOT_SATISFIED StateChange = "SATISFIED TO NOT SATISFIED"
-	NEW                        StateChange = "NEW"
-	REMOVED                    StateChange = "REMOVED"
-	UNCHANGED                  StateChange = "UNCHANGED"
+	NOT_SATISFIED_TO_SATISFIED  StateChange = "NOT SATISFIED TO SATISFIED"
+	SATISFIED_TO_NOT_SATISFIED  StateChange = "SATISFIED TO NOT SATISFIED"
+	SATISFIED_TO_ERROR          StateChange = "SATISFIED TO ERROR"
+	SATISFIED_TO_NOT_APPLICABLE StateChange = "SATISFIED TO NOT APPLICABLE"
+	NEW                         StateChange = "NEW"
+	REMOVED                     StateChange = "REMOVED"
+	UNCHANGED                   StateChange = "UNCHANGED"
 )
 
 type ResultComparison struct {
@@ -283,7 +285,15 @@ func compareFindings(finding *oscalTypes.Finding, comparedFinding *oscalTypes.Fi
 			comparedStatus := comparedFinding.Target.Status.State
 
 			if status == "not-satisfied" && comparedStatus == "satisfied" {
-				state = SATISFIED_TO_NOT_SATISFIED
+				// Validations that could not be evaluated or don't apply are not a failure of the control
+				switch finding.Target.Status.Reason {
+				case "error":
+					state = SATISFIED_TO_ERROR
+				case "not-applicable":
+					state = SATISFIED_TO_NOT_APPLICABLE
+				default:
+					state = SATISFIED_TO_NOT_SATISFIED
+				}
 			} else if status == "satisfied" && comparedStatus == "not-satisfied" {
 				state = NOT_SATISFIED_TO_SATISFIED
 			}
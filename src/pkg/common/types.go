@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalValidation "github.com/defenseunicorns/go-oscal/src/pkg/validation"
@@ -15,8 +16,10 @@ import (
 	"github.com/defenseunicorns/lula/src/config"
 	"github.com/defenseunicorns/lula/src/pkg/common/schemas"
 	"github.com/defenseunicorns/lula/src/pkg/domains/api"
+	"github.com/defenseunicorns/lula/src/pkg/domains/command"
 	"github.com/defenseunicorns/lula/src/pkg/domains/files"
 	kube "github.com/defenseunicorns/lula/src/pkg/domains/kubernetes"
+	"github.com/defenseunicorns/lula/src/pkg/providers/cel"
 	"github.com/defenseunicorns/lula/src/pkg/providers/kyverno"
 	"github.com/defenseunicorns/lula/src/pkg/providers/opa"
 	"github.com/defenseunicorns/lula/src/types"
@@ -30,6 +33,7 @@ var (
 	ErrInvalidDomain   = errors.New("domain is invalid")
 	ErrInvalidProvider = errors.New("provider is invalid")
 	ErrInvalidTest     = errors.New("test is invalid")
+	ErrInvalidTimeout  = errors.New("timeout is invalid")
 )
 
 // Data structures for ingesting validation data
@@ -38,6 +42,7 @@ type Validation struct {
 	Metadata    *Metadata                   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	Provider    *Provider                   `json:"provider,omitempty" yaml:"provider,omitempty"`
 	Domain      *Domain                     `json:"domain,omitempty" yaml:"domain,omitempty"`
+	Domains     []NamedDomain               `json:"domains,omitempty" yaml:"domains,omitempty"`
 	Tests       *[]types.LulaValidationTest `json:"tests,omitempty" yaml:"tests,omitempty"`
 }
 
@@ -88,15 +93,17 @@ func (v *Validation) ToResource() (resource *oscalTypes.Resource, err error) {
 	}, nil
 }
 
-// Metadata is a structure that contains the name and uuid of a validation
+// Metadata is a structure that contains the name, uuid and timeout of a validation
 type Metadata struct {
 	Name string `json:"name" yaml:"name"`
 	UUID string `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	// Timeout is the maximum duration of the validation, e.g. 5m
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // Domain is a structure that contains the domain type and the corresponding spec
 type Domain struct {
-	// Type is the type of domain: enum: kubernetes, api
+	// Type is the type of domain: enum: kubernetes, api, file, command
 	Type string `json:"type" yaml:"type"`
 	// KubernetesSpec is the specification for a Kubernetes domain, required if type is kubernetes
 	KubernetesSpec *kube.KubernetesSpec `json:"kubernetes-spec,omitempty" yaml:"kubernetes-spec,omitempty"`
@@ -104,12 +111,22 @@ type Domain struct {
 	ApiSpec *api.ApiSpec `json:"api-spec,omitempty" yaml:"api-spec,omitempty"`
 	// FileSpec is the specification for a File domain, required if type is file
 	FileSpec *files.Spec `json:"file-spec,omitempty" yaml:"file-spec,omitempty"`
+	// CommandSpec is the specification for a Command domain, required if type is command
+	CommandSpec *command.Spec `json:"command-spec,omitempty" yaml:"command-spec,omitempty"`
+}
+
+// NamedDomain is a domain in a cross-domain validation, whose resources are
+// keyed by its name
+type NamedDomain struct {
+	Name   string `json:"name" yaml:"name"`
+	Domain `json:",inline" yaml:",inline"`
 }
 
 type Provider struct {
 	Type        string               `json:"type" yaml:"type"`
 	OpaSpec     *opa.OpaSpec         `json:"opa-spec,omitempty" yaml:"opa-spec,omitempty"`
 	KyvernoSpec *kyverno.KyvernoSpec `json:"kyverno-spec,omitempty" yaml:"kyverno-spec,omitempty"`
+	CelSpec     *cel.CelSpec         `json:"cel-spec,omitempty" yaml:"cel-spec,omitempty"`
 }
 
 // Lint is a convenience method to lint a Validation object
@@ -122,7 +139,7 @@ func (validation *Validation) Lint() oscalValidation.ValidationResult {
 }
 
 // ToLulaValidation converts a Validation object to a LulaValidation object
-func (validation *Validation) ToLulaValidation(uuid string) (lulaValidation types.LulaValidation, err error) {
+func (validation *Validation) ToLulaValidation(ctx context.Context, uuid string) (lulaValidation types.LulaValidation, err error) {
 	// set uuid
 	lulaValidation.UUID = uuid
 
@@ -150,15 +167,23 @@ func (validation *Validation) ToLulaValidation(uuid string) (lulaValidation type
 	}
 
 	// Construct the lulaValidation object
-	// TODO: Is there a better location for context?
-	ctx := context.Background()
-
-	domain, err := GetDomain(validation.Domain)
-	if domain == nil {
-		return lulaValidation, fmt.Errorf("%w: %s", ErrInvalidDomain, validation.Domain.Type)
-	}
-	if err != nil {
-		return lulaValidation, fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+	var domain types.Domain
+	if len(validation.Domains) > 0 {
+		if validation.Domain != nil {
+			return lulaValidation, fmt.Errorf("%w: only one of domain or domains can be specified", ErrInvalidDomain)
+		}
+		domain, err = GetDomains(validation.Domains)
+		if err != nil {
+			return lulaValidation, fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+		}
+	} else {
+		domain, err = GetDomain(validation.Domain)
+		if domain == nil {
+			return lulaValidation, fmt.Errorf("%w: %s", ErrInvalidDomain, validation.Domain.Type)
+		}
+		if err != nil {
+			return lulaValidation, fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+		}
 	}
 	lulaValidation.Domain = &domain
 
@@ -176,6 +201,13 @@ func (validation *Validation) ToLulaValidation(uuid string) (lulaValidation type
 		lulaValidation.Name = "lula-validation"
 	} else {
 		lulaValidation.Name = validation.Metadata.Name
+		if validation.Metadata.Timeout != "" {
+			timeout, err := time.ParseDuration(validation.Metadata.Timeout)
+			if err != nil || timeout <= 0 {
+				return lulaValidation, fmt.Errorf("%w: %q must be a positive duration", ErrInvalidTimeout, validation.Metadata.Timeout)
+			}
+			lulaValidation.Timeout = timeout
+		}
 	}
 
 	// Add tests if they exist
@@ -1,6 +1,7 @@
 package common_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -211,6 +212,129 @@ tests:
 			expectErr:       true,
 			expectedErrType: common.ErrInvalidSchema,
 		},
+		{
+			name: "Valid cross-domain validation",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-domains"
+domains:
+  - name: cluster
+    type: "kubernetes"
+    kubernetes-spec:
+      resources: []
+  - name: host
+    type: "command"
+    command-spec:
+      commands:
+        - name: version
+          command: echo
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+		},
+		{
+			name: "Invalid cross-domain validation, domain and domains",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-domains"
+domain:
+  type: "kubernetes"
+  kubernetes-spec:
+    resources: []
+domains:
+  - name: cluster
+    type: "kubernetes"
+    kubernetes-spec:
+      resources: []
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+			expectErr:       true,
+			expectedErrType: common.ErrInvalidSchema,
+		},
+		{
+			name: "Invalid cross-domain validation, missing name",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-domains"
+domains:
+  - type: "kubernetes"
+    kubernetes-spec:
+      resources: []
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+			expectErr:       true,
+			expectedErrType: common.ErrInvalidDomain,
+		},
+		{
+			name: "Invalid cross-domain validation, duplicate names",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-domains"
+domains:
+  - name: cluster
+    type: "kubernetes"
+    kubernetes-spec:
+      resources: []
+  - name: cluster
+    type: "kubernetes"
+    kubernetes-spec:
+      resources: []
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+			expectErr:       true,
+			expectedErrType: common.ErrInvalidDomain,
+		},
+		{
+			name: "Valid timeout",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-timeout"
+  timeout: 5m
+domain:
+  type: "kubernetes"
+  kubernetes-spec:
+    resources: []
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+		},
+		{
+			name: "Invalid timeout",
+			inputYaml: []byte(`
+lula-version: "1.0.0"
+metadata:
+  name: "test-timeout"
+  timeout: soon
+domain:
+  type: "kubernetes"
+  kubernetes-spec:
+    resources: []
+provider:
+  type: "opa"
+  opa-spec:
+    rego: "package validate\n\ndefault validate = false"
+`),
+			expectErr:       true,
+			expectedErrType: common.ErrInvalidTimeout,
+		},
 	}
 
 	for _, tt := range tests {
@@ -221,7 +345,7 @@ tests:
 				t.Fatalf("UnmarshalYaml failed: %v", err)
 			}
 
-			_, err = validation.ToLulaValidation("")
+			_, err = validation.ToLulaValidation(context.Background(), "")
 			if (err != nil) != tt.expectErr {
 				t.Fatalf("expected error: %v, got: %v", tt.expectErr, err)
 			}
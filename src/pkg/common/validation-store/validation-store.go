@@ -2,9 +2,15 @@ package validationstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/defenseunicorns/go-oscal/src/pkg/files"
 	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
@@ -12,6 +18,7 @@ import (
 
 	"github.com/defenseunicorns/lula/src/pkg/common"
 	"github.com/defenseunicorns/lula/src/pkg/common/oscal"
+	"github.com/defenseunicorns/lula/src/pkg/common/result"
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	"github.com/defenseunicorns/lula/src/types"
 )
@@ -46,7 +53,7 @@ func (v *ValidationStore) Count() int {
 }
 
 // AddValidation adds a validation to the store
-func (v *ValidationStore) AddValidation(validation *common.Validation) (id string, err error) {
+func (v *ValidationStore) AddValidation(ctx context.Context, validation *common.Validation) (id string, err error) {
 	if validation.Metadata == nil {
 		validation.Metadata = &common.Metadata{}
 	}
@@ -55,7 +62,7 @@ func (v *ValidationStore) AddValidation(validation *common.Validation) (id strin
 		validation.Metadata.UUID = uuid.NewUUID()
 	}
 
-	lulaValidation, err := validation.ToLulaValidation(validation.Metadata.UUID)
+	lulaValidation, err := validation.ToLulaValidation(ctx, validation.Metadata.UUID)
 	v.validationMap[validation.Metadata.UUID] = &lulaValidation
 
 	if err != nil {
@@ -72,7 +79,7 @@ func (v *ValidationStore) AddLulaValidation(validation *types.LulaValidation, id
 }
 
 // GetLulaValidation gets the LulaValidation from the store
-func (v *ValidationStore) GetLulaValidation(id string) (validation *types.LulaValidation, err error) {
+func (v *ValidationStore) GetLulaValidation(ctx context.Context, id string) (validation *types.LulaValidation, err error) {
 	trimmedId := common.TrimIdPrefix(id)
 
 	if validation, ok := v.validationMap[trimmedId]; ok {
@@ -80,7 +87,7 @@ func (v *ValidationStore) GetLulaValidation(id string) (validation *types.LulaVa
 	}
 
 	if validationString, ok := v.backMatterMap[trimmedId]; ok {
-		lulaValidation, err := common.ValidationFromString(validationString, trimmedId)
+		lulaValidation, err := common.ValidationFromString(ctx, validationString, trimmedId)
 		if err != nil {
 			return &lulaValidation, err
 		}
@@ -95,10 +102,8 @@ func (v *ValidationStore) GetLulaValidation(id string) (validation *types.LulaVa
 func (v *ValidationStore) DryRun() (executable bool, msg string) {
 	executableValidations := make([]string, 0)
 	for k, val := range v.validationMap {
-		if val != nil && val.Domain != nil {
-			if (*val.Domain).IsExecutable() {
-				executableValidations = append(executableValidations, k)
-			}
+		if val != nil && val.IsExecutable() {
+			executableValidations = append(executableValidations, k)
 		}
 	}
 	if len(executableValidations) > 0 {
@@ -107,112 +112,221 @@ func (v *ValidationStore) DryRun() (executable bool, msg string) {
 	return false, "No validation is executable"
 }
 
-// RunValidations runs the validations in the store
-func (v *ValidationStore) RunValidations(ctx context.Context, confirmExecution, saveResources bool, outputsDir string) []oscalTypes.Observation {
-	observations := make([]oscalTypes.Observation, 0, len(v.validationMap))
-
+// RunValidations runs the validations in the store, returning their
+// observations ordered by validation ID. Up to concurrency validations are run
+// in parallel; executable validations can change what others collect, so they
+// are run one at a time after the rest. Validations without a timeout of
+// their own are bounded by timeout, if it is set; a validation that times out
+// is recorded as timed-out and the rest still run. A validation stored under
+// several IDs is only run once.
+func (v *ValidationStore) RunValidations(ctx context.Context, confirmExecution, saveResources bool, outputsDir string, concurrency int, timeout time.Duration) []oscalTypes.Observation {
+	// Share the resources collected by identical domains and resource rules across validations
+	ctx = types.WithResourceCache(ctx, types.NewResourceCache())
+	opts := []types.LulaValidationOption{types.ExecutionAllowed(confirmExecution), types.WithTimeout(timeout)}
+
+	ids := make([]string, 0, len(v.validationMap))
 	for k, val := range v.validationMap {
 		if val != nil {
-			// Create observation for each non-nil validation
-			completedText := "evaluated"
-			spinnerMessage := fmt.Sprintf("Running validation %s", k)
-			spinner := message.NewProgressSpinner("%s", spinnerMessage)
-			defer spinner.Stop()
-			err := val.Validate(ctx, types.ExecutionAllowed(confirmExecution))
+			ids = append(ids, k)
+		}
+	}
+	sort.Strings(ids)
+
+	// Group the IDs of each validation, in the order of their first ID
+	vals := make([]*types.LulaValidation, 0, len(ids))
+	valIds := make(map[*types.LulaValidation][]string, len(ids))
+	for _, k := range ids {
+		val := v.validationMap[k]
+		if _, ok := valIds[val]; !ok {
+			vals = append(vals, val)
+		}
+		valIds[val] = append(valIds[val], k)
+	}
+
+	var errs map[*types.LulaValidation]error
+	if concurrency > 1 {
+		errs = validateConcurrently(ctx, vals, valIds, concurrency, opts...)
+	} else {
+		errs = make(map[*types.LulaValidation]error, len(vals))
+		for _, val := range vals {
+			spinner := message.NewProgressSpinner("Running validation %s", valIds[val][0])
+			errs[val] = validate(ctx, val, opts...)
+			reportValidation(spinner, valIds[val], val, errs[val])
+		}
+	}
+
+	observations := make([]oscalTypes.Observation, 0, len(ids))
+	for _, k := range ids {
+		val := v.validationMap[k]
+
+		// Save Resources if specified
+		var resourceHref string
+		if saveResources {
+			resourceUuid := uuid.NewUUID()
+			// Create a remote resource file -> create directory 'resources' in the assessment-results directory -> create file with UUID as name
+			filename := fmt.Sprintf("%s.json", resourceUuid)
+			resourceFile := filepath.Join(outputsDir, "resources", filename)
+			err := os.MkdirAll(filepath.Dir(resourceFile), os.ModePerm) // #nosec G301
 			if err != nil {
-				message.Debugf("Error running validation %s: %v", k, err)
-				// Update validation with failed results
-				val.Result.State = "not-satisfied"
-				val.Result.Observations = map[string]string{
-					"Error running validation": err.Error(),
-				}
-				completedText = "NOT evaluated"
+				message.Debugf("Error creating directory for remote resource: %v", err)
 			}
-
-			// Update individual result state
-			if val.Result.Passing > 0 && val.Result.Failing <= 0 {
-				val.Result.State = "satisfied"
-			} else {
-				val.Result.State = "not-satisfied"
+			jsonData := val.GetDomainResourcesAsJSON()
+			err = files.WriteOutput(jsonData, resourceFile)
+			if err != nil {
+				message.Debugf("Error writing remote resource file: %v", err)
 			}
+			resourceHref = fmt.Sprintf("file://./resources/%s", filename)
+		}
 
-			// Add the observation to the observation map
-			var remarks string
-			if len(val.Result.Observations) > 0 {
-				for k, v := range val.Result.Observations {
-					remarks += fmt.Sprintf("%s: %s\n", k, v)
-				}
-			}
+		// Create an observation
+		relevantEvidence := oscal.CreateRelevantEvidence(val.Result.State, val.Result.Observations)
+		observation := oscal.CreateObservation("TEST", relevantEvidence, val, resourceHref, "[TEST]: %s - %s\n", k, val.Name)
+		v.observationMap[k] = &observation
+		observations = append(observations, observation)
+	}
+	return observations
+}
 
-			// Save Resources if specified
-			var resourceHref string
-			if saveResources {
-				resourceUuid := uuid.NewUUID()
-				// Create a remote resource file -> create directory 'resources' in the assessment-results directory -> create file with UUID as name
-				filename := fmt.Sprintf("%s.json", resourceUuid)
-				resourceFile := filepath.Join(outputsDir, "resources", filename)
-				err := os.MkdirAll(filepath.Dir(resourceFile), os.ModePerm) // #nosec G301
-				if err != nil {
-					message.Debugf("Error creating directory for remote resource: %v", err)
-				}
-				jsonData := val.GetDomainResourcesAsJSON()
-				err = files.WriteOutput(jsonData, resourceFile)
-				if err != nil {
-					message.Debugf("Error writing remote resource file: %v", err)
-				}
-				resourceHref = fmt.Sprintf("file://./resources/%s", filename)
-			}
+// validate runs the validation and resolves its result state. A validation
+// that could not be evaluated records the error instead of a result.
+func validate(ctx context.Context, val *types.LulaValidation, opts ...types.LulaValidationOption) error {
+	err := val.Validate(ctx, opts...)
+	if err != nil {
+		message.Debugf("Error running validation %s: %v", val.Name, err)
+		val.Result.Observations = map[string]interface{}{
+			"Error running validation": err.Error(),
+		}
+	}
 
-			// Create an observation
-			relevantEvidence := &[]oscalTypes.RelevantEvidence{
-				{
-					Description: fmt.Sprintf("Result: %s\n", val.Result.State),
-					Remarks:     remarks,
-				},
-			}
-			observation := oscal.CreateObservation("TEST", relevantEvidence, val, resourceHref, "[TEST]: %s - %s\n", k, val.Name)
-			v.observationMap[k] = &observation
-			observations = append(observations, observation)
+	// A validation that could not be evaluated is not a failure of the control
+	switch {
+	case errors.Is(err, types.ErrValidationTimeout):
+		val.Result.State = "timed-out"
+	case err != nil:
+		val.Result.State = "error"
+	case val.Result.State == "error" || val.Result.State == "not-applicable":
+		// Set by the provider or when the validation could not be resolved
+	case val.Result.Passing > 0 && val.Result.Failing <= 0:
+		val.Result.State = "satisfied"
+	default:
+		val.Result.State = "not-satisfied"
+	}
+	return err
+}
+
+// reportValidation stops the spinner with the outcome of the validation,
+// printing it once for each of the IDs the validation is stored under.
+func reportValidation(spinner *message.Spinner, ids []string, val *types.LulaValidation, err error) {
+	completedText := "evaluated"
+	switch {
+	case errors.Is(err, types.ErrValidationTimeout):
+		completedText = "timed out"
+	case err != nil:
+		completedText = "NOT evaluated"
+	}
 
-			spinner.Successf("%s -> %s -> %s", spinnerMessage, completedText, val.Result.State)
+	for i, k := range ids {
+		text := fmt.Sprintf("Running validation %s -> %s -> %s", k, completedText, val.Result.State)
+		if i == 0 {
+			spinner.Successf("%s", text)
+		} else {
+			message.Successf("%s", text)
 		}
 	}
-	return observations
 }
 
-// GetObservation returns the observation with the given ID as well as pass status
+// validateConcurrently runs the validations with up to concurrency workers,
+// returning the error of each validation. Executable validations are run one
+// at a time after the rest. Each validation is reported as it completes, while
+// the spinner lists the IDs of the validations still running.
+func validateConcurrently(ctx context.Context, vals []*types.LulaValidation, ids map[*types.LulaValidation][]string, concurrency int, opts ...types.LulaValidationOption) map[*types.LulaValidation]error {
+	var parallel, serial []*types.LulaValidation
+	for _, val := range vals {
+		if val.IsExecutable() {
+			serial = append(serial, val)
+		} else {
+			parallel = append(parallel, val)
+		}
+	}
+
+	var mu sync.Mutex
+	var spinner *message.Spinner
+	running := make([]string, 0, concurrency)
+	errs := make(map[*types.LulaValidation]error, len(vals))
+	// progress starts or updates the spinner, mu must be held
+	progress := func() {
+		spinner = message.NewProgressSpinner("Running validations (%d/%d): %s", len(errs), len(vals), strings.Join(running, ", "))
+	}
+	run := func(val *types.LulaValidation) {
+		k := ids[val][0]
+		mu.Lock()
+		running = append(running, k)
+		progress()
+		mu.Unlock()
+
+		err := validate(ctx, val, opts...)
+
+		mu.Lock()
+		defer mu.Unlock()
+		errs[val] = err
+		running = slices.DeleteFunc(running, func(r string) bool { return r == k })
+		reportValidation(spinner, ids[val], val, err)
+		if len(running) > 0 {
+			progress()
+		}
+	}
+
+	work := make(chan *types.LulaValidation)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(parallel)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for val := range work {
+				run(val)
+			}
+		}()
+	}
+	for _, val := range parallel {
+		work <- val
+	}
+	close(work)
+	wg.Wait()
+
+	for _, val := range serial {
+		run(val)
+	}
+
+	return errs
+}
+
+// GetRelatedObservation returns the observation with the given ID as well as pass status
 func (v *ValidationStore) GetRelatedObservation(id string) (oscalTypes.RelatedObservation, bool) {
+	relatedObservation, state := v.GetRelatedObservationState(id)
+	return relatedObservation, state == "satisfied"
+}
+
+// GetRelatedObservationState returns the observation with the given ID as well as its result state,
+// the state is empty if the observation is not found
+func (v *ValidationStore) GetRelatedObservationState(id string) (oscalTypes.RelatedObservation, string) {
 	trimmedId := common.TrimIdPrefix(id)
 	observation, ok := v.observationMap[trimmedId]
 	if !ok {
-		return oscalTypes.RelatedObservation{}, false
-	}
-	pass := false
-
-	// check all descriptions in relevant evidence are satisfied
-	if observation.RelevantEvidence != nil {
-		for _, e := range *observation.RelevantEvidence {
-			if e.Description == "Result: satisfied\n" {
-				pass = true
-			} else { // if any are not satisfied, return false
-				pass = false
-				break
-			}
-		}
+		return oscalTypes.RelatedObservation{}, ""
 	}
 
 	return oscalTypes.RelatedObservation{
 		ObservationUuid: observation.UUID,
-	}, pass
+	}, result.ObservationState(observation.RelevantEvidence)
 }
 
 // RunTests executes any tests defined on the validations in the validation store
-func (v *ValidationStore) RunTests(ctx context.Context) map[string]types.LulaValidationTestReport {
+func (v *ValidationStore) RunTests(ctx context.Context, opts ...types.LulaValidationOption) map[string]types.LulaValidationTestReport {
 	testReportMap := make(map[string]types.LulaValidationTestReport)
 
 	for uuid, validation := range v.validationMap {
 		// TODO: should test results be saved, e.g., if printResources is true?
-		testReport, err := validation.RunTests(ctx, false)
+		testReport, err := validation.RunTests(ctx, false, opts...)
 		if err != nil {
 			testReportMap[uuid] = types.LulaValidationTestReport{
 				Name: validation.Name,
@@ -2,7 +2,10 @@ package validationstore_test
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
@@ -53,7 +56,7 @@ func TestAddValidation(t *testing.T) {
 	validation := generateValidation(t, validationPath)
 	v := validationstore.NewValidationStore()
 
-	id, err := v.AddValidation(&validation)
+	id, err := v.AddValidation(context.Background(), &validation)
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
 	}
@@ -65,8 +68,8 @@ func TestAddValidation(t *testing.T) {
 func TestGetLulaValidation(t *testing.T) {
 	validation := generateValidation(t, validationPath)
 	v := validationstore.NewValidationStore()
-	id, _ := v.AddValidation(&validation)
-	lulaValidation, err := v.GetLulaValidation(id)
+	id, _ := v.AddValidation(context.Background(), &validation)
+	lulaValidation, err := v.GetLulaValidation(context.Background(), id)
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
 	}
@@ -78,6 +81,8 @@ func TestGetLulaValidation(t *testing.T) {
 func TestDryRun(t *testing.T) {
 	validation := generateValidation(t, validationPath)
 	executableValidation := generateValidation(t, executableValidationPath)
+	executableProviderValidation := generateValidation(t, validationPath)
+	executableProviderValidation.Provider.OpaSpec.Executable = true
 
 	tests := []struct {
 		name               string
@@ -108,6 +113,14 @@ func TestDryRun(t *testing.T) {
 			},
 			expectedExecutable: true,
 		},
+		{
+			name: "Executable provider",
+			validations: []common.Validation{
+				validation,
+				executableProviderValidation,
+			},
+			expectedExecutable: true,
+		},
 		{
 			name:               "No validations",
 			validations:        []common.Validation{},
@@ -119,7 +132,7 @@ func TestDryRun(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			v := validationstore.NewValidationStore()
 			for _, validation := range tt.validations {
-				_, err := v.AddValidation(&validation)
+				_, err := v.AddValidation(context.Background(), &validation)
 				require.NoError(t, err)
 			}
 
@@ -169,7 +182,7 @@ func TestRunValidations(t *testing.T) {
 				v.AddLulaValidation(validation, uuid.NewUUID())
 			}
 
-			observations := v.RunValidations(context.Background(), true, false, "")
+			observations := v.RunValidations(context.Background(), true, false, "", 1, 0)
 			if len(observations) != tt.expectedObservations {
 				t.Errorf("Expected %d observations, but got %d", tt.expectedObservations, len(observations))
 			}
@@ -184,10 +197,10 @@ func TestRunValidations(t *testing.T) {
 		v.AddLulaValidation(validation, validationUuid)
 
 		// Run the validations on the store
-		_ = v.RunValidations(context.Background(), true, false, "")
+		_ = v.RunValidations(context.Background(), true, false, "", 1, 0)
 
 		// Check that the validation data has been stored in v, state should be satisfied
-		val, err := v.GetLulaValidation(validationUuid)
+		val, err := v.GetLulaValidation(context.Background(), validationUuid)
 		require.NoError(t, err)
 		require.NotNil(t, val)
 		require.NotNil(t, val.Result)
@@ -203,7 +216,7 @@ func TestGetRelatedObservation(t *testing.T) {
 	v.AddLulaValidation(validationPass, "1")
 	v.AddLulaValidation(validationFail, "2")
 
-	v.RunValidations(context.Background(), true, false, "")
+	v.RunValidations(context.Background(), true, false, "", 1, 0)
 
 	tests := []struct {
 		name               string
@@ -252,13 +265,13 @@ func TestRunTests(t *testing.T) {
 	validation := generateValidation(t, "./testdata/validation.yaml")
 	validationWithTests := generateValidation(t, "./testdata/validation-with-tests.yaml")
 
-	idValidation, err := v.AddValidation(&validation)
+	idValidation, err := v.AddValidation(context.Background(), &validation)
 	require.NoError(t, err)
-	idValidationWithTests, err := v.AddValidation(&validationWithTests)
+	idValidationWithTests, err := v.AddValidation(context.Background(), &validationWithTests)
 	require.NoError(t, err)
 
 	// Run validations to populate domain resources for tests
-	v.RunValidations(ctx, true, false, "")
+	v.RunValidations(ctx, true, false, "", 1, 0)
 
 	// Run tests
 	testReport := v.RunTests(ctx)
@@ -278,3 +291,175 @@ func TestRunTests(t *testing.T) {
 	assert.True(t, reportValidationWithTests.TestResults[0].Pass)
 	assert.True(t, reportValidationWithTests.TestResults[1].Pass)
 }
+
+// concurrencyDomain records the maximum number of domains collecting resources at once
+type concurrencyDomain struct {
+	executable bool
+	tracker    *concurrencyTracker
+}
+
+type concurrencyTracker struct {
+	mu                 sync.Mutex
+	inFlight, max      int
+	executableOverlaps int
+}
+
+func (d concurrencyDomain) GetResources(context.Context) (types.DomainResources, error) {
+	d.tracker.mu.Lock()
+	d.tracker.inFlight++
+	d.tracker.max = max(d.tracker.max, d.tracker.inFlight)
+	if d.executable && d.tracker.inFlight > 1 {
+		d.tracker.executableOverlaps++
+	}
+	d.tracker.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	d.tracker.mu.Lock()
+	d.tracker.inFlight--
+	d.tracker.mu.Unlock()
+	return types.DomainResources{"executable": d.executable}, nil
+}
+
+func (d concurrencyDomain) IsExecutable() bool { return d.executable }
+
+type passingProvider struct{}
+
+func (passingProvider) Evaluate(context.Context, types.DomainResources) (types.Result, error) {
+	return types.Result{Passing: 1}, nil
+}
+
+func (passingProvider) IsExecutable() bool { return false }
+
+func TestRunValidationsConcurrently(t *testing.T) {
+	message.NoProgress = true
+	tracker := &concurrencyTracker{}
+	v := validationstore.NewValidationStore()
+
+	ids := make([]string, 0, 12)
+	for i := range 12 {
+		var domain types.Domain = concurrencyDomain{executable: i%4 == 0, tracker: tracker}
+		var provider types.Provider = passingProvider{}
+		id := fmt.Sprintf("validation-%02d", i)
+		v.AddLulaValidation(&types.LulaValidation{Name: id, Domain: &domain, Provider: &provider}, id)
+		ids = append(ids, id)
+	}
+
+	observations := v.RunValidations(context.Background(), true, false, "", 4, 0)
+	require.Len(t, observations, 12)
+	for i, observation := range observations {
+		require.Equal(t, fmt.Sprintf("[TEST]: %s - %s\n", ids[i], ids[i]), observation.Description)
+		val, err := v.GetLulaValidation(context.Background(), ids[i])
+		require.NoError(t, err)
+		require.Equal(t, "satisfied", val.Result.State)
+	}
+	require.Equal(t, 4, tracker.max)
+	require.Zero(t, tracker.executableOverlaps)
+}
+
+// hangingDomain collects its resources once ctx is done
+type hangingDomain struct{}
+
+func (hangingDomain) GetResources(ctx context.Context) (types.DomainResources, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (hangingDomain) IsExecutable() bool { return false }
+
+func TestRunValidationsTimeout(t *testing.T) {
+	message.NoProgress = true
+	v := validationstore.NewValidationStore()
+
+	var hanging types.Domain = hangingDomain{}
+	var provider types.Provider = passingProvider{}
+	v.AddLulaValidation(&types.LulaValidation{Name: "hanging", Domain: &hanging, Provider: &provider}, "1")
+	v.AddLulaValidation(types.CreatePassingLulaValidation("passing"), "2")
+
+	observations := v.RunValidations(context.Background(), true, false, "", 1, 10*time.Millisecond)
+	require.Len(t, observations, 2)
+	require.Equal(t, "Result: timed-out\n", (*observations[0].RelevantEvidence)[0].Description)
+	require.Equal(t, "Result: satisfied\n", (*observations[1].RelevantEvidence)[0].Description)
+
+	_, pass := v.GetRelatedObservation("1")
+	require.False(t, pass)
+}
+
+// failingDomain fails to collect its resources
+type failingDomain struct{}
+
+func (failingDomain) GetResources(context.Context) (types.DomainResources, error) {
+	return nil, fmt.Errorf("connection refused")
+}
+
+func (failingDomain) IsExecutable() bool { return false }
+
+type notApplicableProvider struct{}
+
+func (notApplicableProvider) Evaluate(context.Context, types.DomainResources) (types.Result, error) {
+	return types.Result{State: "not-applicable"}, nil
+}
+
+func (notApplicableProvider) IsExecutable() bool { return false }
+
+func TestRunValidationsStates(t *testing.T) {
+	message.NoProgress = true
+	v := validationstore.NewValidationStore()
+
+	var failing types.Domain = failingDomain{}
+	var domain types.Domain = concurrencyDomain{tracker: &concurrencyTracker{}}
+	var passing types.Provider = passingProvider{}
+	var notApplicable types.Provider = notApplicableProvider{}
+	v.AddLulaValidation(&types.LulaValidation{Name: "error", Domain: &failing, Provider: &passing}, "1")
+	v.AddLulaValidation(&types.LulaValidation{Name: "not-applicable", Domain: &domain, Provider: &notApplicable}, "2")
+	v.AddLulaValidation(types.CreateFailingLulaValidation("failing"), "3")
+
+	observations := v.RunValidations(context.Background(), true, false, "", 1, 0)
+	require.Len(t, observations, 3)
+	require.Equal(t, "Result: error\n", (*observations[0].RelevantEvidence)[0].Description)
+	require.Equal(t, "Result: not-applicable\n", (*observations[1].RelevantEvidence)[0].Description)
+	require.Equal(t, "Result: not-satisfied\n", (*observations[2].RelevantEvidence)[0].Description)
+
+	for id, want := range map[string]string{"1": "error", "2": "not-applicable", "3": "not-satisfied", "4": ""} {
+		_, state := v.GetRelatedObservationState(id)
+		require.Equal(t, want, state)
+	}
+}
+
+func TestRunValidationsSerialAndConcurrent(t *testing.T) {
+	message.NoProgress = true
+
+	// run creates the same validations, some stored under several IDs, and runs them
+	run := func(concurrency int) []oscalTypes.Observation {
+		var failing types.Domain = failingDomain{}
+		var hanging types.Domain = hangingDomain{}
+		var domain types.Domain = concurrencyDomain{tracker: &concurrencyTracker{}}
+		var passing types.Provider = passingProvider{}
+		var notApplicable types.Provider = notApplicableProvider{}
+
+		shared := types.CreatePassingLulaValidation("shared")
+		timedOut := &types.LulaValidation{Name: "timed-out", Domain: &hanging, Provider: &passing}
+		v := validationstore.NewValidationStore()
+		v.AddLulaValidation(shared, "1")
+		v.AddLulaValidation(shared, "2")
+		v.AddLulaValidation(&types.LulaValidation{Name: "error", Domain: &failing, Provider: &passing}, "3")
+		v.AddLulaValidation(&types.LulaValidation{Name: "not-applicable", Domain: &domain, Provider: &notApplicable}, "4")
+		v.AddLulaValidation(types.CreateFailingLulaValidation("failing"), "5")
+		v.AddLulaValidation(timedOut, "6")
+		v.AddLulaValidation(timedOut, "7")
+
+		observations := v.RunValidations(context.Background(), true, false, "", concurrency, 10*time.Millisecond)
+		for i := range observations {
+			observations[i].UUID = ""
+			observations[i].Collected = time.Time{}
+		}
+		return observations
+	}
+
+	serial := run(1)
+	require.Len(t, serial, 7)
+	for i, want := range []string{"satisfied", "satisfied", "error", "not-applicable", "not-satisfied", "timed-out", "timed-out"} {
+		require.Equal(t, fmt.Sprintf("Result: %s\n", want), (*serial[i].RelevantEvidence)[0].Description)
+	}
+	require.Equal(t, serial, run(4))
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/defenseunicorns/lula/src/pkg/common/composition"
 	"github.com/defenseunicorns/lula/src/pkg/message"
@@ -58,3 +59,25 @@ func WithTests(runTests bool) Option {
 		return nil
 	}
 }
+
+// WithTimeout sets the timeout of validations that don't set their own, zero for no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *Validator) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout cannot be negative, got %s", timeout)
+		}
+		v.timeout = timeout
+		return nil
+	}
+}
+
+// WithConcurrency sets the maximum number of validations run in parallel
+func WithConcurrency(concurrency int) Option {
+	return func(v *Validator) error {
+		if concurrency < 1 {
+			return fmt.Errorf("concurrency must be at least 1, got %d", concurrency)
+		}
+		v.concurrency = concurrency
+		return nil
+	}
+}
@@ -27,10 +27,12 @@ type Validator struct {
 	outputsDir                   string
 	saveResources                bool
 	runTests                     bool
+	concurrency                  int
+	timeout                      time.Duration
 }
 
 func New(opts ...Option) (*Validator, error) {
-	var validator Validator
+	validator := Validator{concurrency: 1}
 
 	for _, opt := range opts {
 		if err := opt(&validator); err != nil {
@@ -143,7 +145,7 @@ func (v *Validator) ValidateOnControlImplementations(ctx context.Context, contro
 	// Create requirement store for all implemented requirements
 	requirementStore := requirementstore.NewRequirementStore(controlImplementations)
 	message.Title("\n🔍 Collecting Requirements and Validations for Target: ", target)
-	requirementStore.ResolveLulaValidations(validationStore)
+	requirementStore.ResolveLulaValidations(ctx, validationStore)
 	reqtStats := requirementStore.GetStats(validationStore)
 	message.Infof("Found %d Implemented Requirements", reqtStats.TotalRequirements)
 	message.Infof("Found %d runnable Lula Validations", reqtStats.TotalValidations)
@@ -162,7 +164,7 @@ func (v *Validator) ValidateOnControlImplementations(ctx context.Context, contro
 
 	// Run Lula validations and generate observations & findings
 	message.Title("\n📐 Running Validations", "")
-	observations := validationStore.RunValidations(ctx, v.runExecutableValidations, v.saveResources, v.outputsDir)
+	observations := validationStore.RunValidations(ctx, v.runExecutableValidations, v.saveResources, v.outputsDir, v.concurrency, v.timeout)
 	message.Title("\n💡 Findings", "")
 	findings := requirementStore.GenerateFindings(validationStore)
 
@@ -184,7 +186,7 @@ func (v *Validator) ValidateOnControlImplementations(ctx context.Context, contro
 
 	if v.runTests {
 		message.Title("\n🧪 Testing", "")
-		testReportsMap := validationStore.RunTests(ctx)
+		testReportsMap := validationStore.RunTests(ctx, types.ExecutionAllowed(v.runExecutableValidations))
 		summary, noTestsRun := types.SummarizeTestReport(testReportsMap)
 		message.Info(summary)
 		if !noTestsRun {
@@ -1,13 +1,14 @@
 package api
 
 import (
-	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/defenseunicorns/lula/src/types"
 )
@@ -17,6 +18,26 @@ type APIResponse struct {
 	Status     string
 	Raw        json.RawMessage
 	Response   any
+	// Attempts is the number of requests sent, including retries
+	Attempts int
+	// Pages is the number of pages fetched for paginated requests
+	Pages int
+	// Headers are the response headers
+	Headers http.Header
+	// TLS is the state of the TLS connection, nil for plain HTTP
+	TLS *tls.ConnectionState
+	// Timing of the phases of the last request sent
+	Timing Timing
+}
+
+// Timing records how long each phase of a request took. Phases that didn't
+// happen, e.g. because a connection was reused, are zero.
+type Timing struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	Total        time.Duration
 }
 
 func (a ApiDomain) makeRequests(ctx context.Context) (types.DomainResources, error) {
@@ -24,7 +45,7 @@ func (a ApiDomain) makeRequests(ctx context.Context) (types.DomainResources, err
 	case <-ctx.Done():
 		return nil, fmt.Errorf("canceled: %s", ctx.Err())
 	default:
-		collection := make(map[string]interface{}, 0)
+		collection := make(types.DomainResources, len(a.Spec.Requests))
 
 		// defaultOpts apply to all requests, but may be overridden by adding an
 		// options block to an individual request.
@@ -38,44 +59,195 @@ func (a ApiDomain) makeRequests(ctx context.Context) (types.DomainResources, err
 			defaultOpts = a.Spec.Options
 		}
 
+		workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
+		if !ok {
+			// if unset, assume lula is already working in the same directory the inputFile is in
+			workDir = "."
+		}
+
 		// configure the default HTTP client using any top-level Options. Individual
 		// requests with overrides (in request.Options.Headers) will get bespoke clients.
-		defaultClient := clientFromOpts(defaultOpts)
-		var errs error
-		for _, request := range a.Spec.Requests {
-			var r io.Reader
-			if request.Body != "" {
-				r = bytes.NewBufferString(request.Body)
+		// Errors configuring the default client are only reported by the requests using it.
+		defaultClient, defaultClientErr := clientFromOpts(defaultOpts, workDir)
+		var defaultAuth authorizer
+		if defaultClientErr == nil {
+			defaultAuth, defaultClientErr = authorizerFromOpts(ctx, defaultOpts, defaultClient, workDir)
+		}
+
+		// requests are made in dependency order, so referenced resources are
+		// already collected
+		order := a.Spec.order
+		if len(order) != len(a.Spec.Requests) {
+			order = make([]int, len(a.Spec.Requests))
+			for i := range order {
+				order[i] = i
 			}
+		}
+		names := make(map[string]bool, len(a.Spec.Requests))
+		for _, request := range a.Spec.Requests {
+			names[request.Name] = true
+		}
 
-			var headers map[string]string
-			var client http.Client
+		var errs error
+		for _, i := range order {
+			request := a.Spec.Requests[i]
+			cfg := requestConfig{names: names}
+			var err error
 
 			if request.Options == nil {
-				headers = defaultOpts.Headers
-				client = defaultClient
+				cfg.headers = defaultOpts.Headers
+				cfg.retry = defaultOpts.Retry
+				cfg.client, cfg.authorize, err = defaultClient, defaultAuth, defaultClientErr
 			} else {
-				headers = request.Options.Headers
-				client = clientFromOpts(request.Options)
+				cfg.headers = request.Options.Headers
+				cfg.retry = request.Options.Retry
+				cfg.client, err = clientFromOpts(request.Options, workDir)
+				if err == nil {
+					cfg.authorize, err = authorizerFromOpts(ctx, request.Options, cfg.client, workDir)
+				}
+			}
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("request %s: %w", request.Name, err))
+				collection[request.Name] = types.DomainResources{"status": 0}
+				continue
 			}
 
-			response, err := doHTTPReq(ctx, client, request.Method, *request.reqURL, r, headers, request.reqParameters)
+			if request.ForEach == "" {
+				dr, err := sendRequest(ctx, request, cfg, collection, nil, false)
+				if err != nil {
+					errs = errors.Join(errs, err)
+				}
+				collection[request.Name] = dr
+				continue
+			}
+
+			// make the request once for each element of the referenced array
+			items, err := forEachItems(request, collection, names)
 			if err != nil {
 				errs = errors.Join(errs, err)
 			}
-			if response != nil {
-				dr := types.DomainResources{
-					"status":     response.Status,
-					"statuscode": response.StatusCode,
-					"raw":        response.Raw,
-					"response":   response.Response,
+			results := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				dr, err := sendRequest(ctx, request, cfg, collection, item, true)
+				if err != nil {
+					errs = errors.Join(errs, err)
 				}
-				collection[request.Name] = dr
-			} else {
-				// If the entire response is empty, return a validly empty resource
-				collection[request.Name] = types.DomainResources{"status": 0}
+				dr["item"] = item
+				results = append(results, dr)
 			}
+			collection[request.Name] = results
 		}
 		return collection, errs
 	}
 }
+
+// requestConfig contains the options that apply to a request
+type requestConfig struct {
+	client    http.Client
+	headers   map[string]string
+	authorize authorizer
+	retry     *RetryOpts
+	// names are the names of all requests in the spec, which may be referenced
+	names map[string]bool
+}
+
+// sendRequest renders any references in the request and sends it. The
+// returned resource is always populated, even if an error is returned.
+func sendRequest(ctx context.Context, request Request, cfg requestConfig, resources types.DomainResources, item any, hasItem bool) (types.DomainResources, error) {
+	req, err := renderRequest(request, cfg, resources, item, hasItem)
+	if err != nil {
+		return types.DomainResources{"status": 0}, fmt.Errorf("request %s: %w", request.Name, err)
+	}
+
+	start := time.Now()
+	var response *APIResponse
+	if request.Pagination != nil {
+		response, err = doPaginatedReq(ctx, req, cfg.retry, request.Pagination)
+	} else {
+		response, err = doHTTPReqWithRetry(ctx, req, cfg.retry)
+	}
+	if response == nil {
+		// If the entire response is empty, return a validly empty resource
+		return types.DomainResources{"status": 0}, err
+	}
+	// the total includes any retries and pages
+	response.Timing.Total = time.Since(start)
+
+	dr := types.DomainResources{
+		"status":     response.Status,
+		"statuscode": response.StatusCode,
+		"raw":        response.Raw,
+		"response":   response.Response,
+		"headers":    headersResource(response.Headers),
+		"tls":        tlsResource(response.TLS),
+		"timing":     timingResource(response.Timing),
+	}
+	if cfg.retry != nil {
+		dr["attempts"] = response.Attempts
+	}
+	if request.Pagination != nil {
+		dr["pages"] = response.Pages
+	}
+	return dr, err
+}
+
+// renderRequest replaces references in the url, parameters, headers and body
+// with values from the resources of earlier requests.
+func renderRequest(request Request, cfg requestConfig, resources types.DomainResources, item any, hasItem bool) (httpRequest, error) {
+	var errs error
+	renderField := func(s string) string {
+		rendered, err := render(s, resources, cfg.names, item, hasItem)
+		errs = errors.Join(errs, err)
+		return rendered
+	}
+
+	req := httpRequest{
+		client:    cfg.client,
+		method:    request.Method,
+		body:      renderField(request.Body),
+		authorize: cfg.authorize,
+	}
+
+	if request.reqURL != nil {
+		req.url = *request.reqURL
+	} else {
+		reqURL, err := url.Parse(renderField(request.URL))
+		if err != nil {
+			errs = errors.Join(errs, errors.New("invalid request url"))
+		} else {
+			req.url = *reqURL
+		}
+	}
+
+	if request.reqParameters != nil {
+		req.params = make(url.Values, len(request.reqParameters))
+		for k, values := range request.reqParameters {
+			for _, v := range values {
+				req.params.Add(k, renderField(v))
+			}
+		}
+	}
+
+	if cfg.headers != nil {
+		req.headers = make(map[string]string, len(cfg.headers))
+		for k, v := range cfg.headers {
+			req.headers[k] = renderField(v)
+		}
+	}
+
+	return req, errs
+}
+
+// forEachItems returns the array referenced by the for-each of the request
+func forEachItems(request Request, resources types.DomainResources, names map[string]bool) ([]any, error) {
+	ref := request.ForEach[2 : len(request.ForEach)-1]
+	v, _, err := resolveReference(ref, resources, names, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %w", request.Name, err)
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("request %s: for-each %s is not an array", request.Name, request.ForEach)
+	}
+	return items, nil
+}
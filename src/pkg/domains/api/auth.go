@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AuthOpts configures how requests are authenticated. At most one of Bearer,
+// Basic and OAuth2 may be set; MTLS may be combined with any of them.
+//
+// Secret values are never part of the spec, they are read from environment
+// variables or files when the requests are made, and are not included in the
+// domain resources.
+type AuthOpts struct {
+	Bearer *BearerAuth `json:"bearer,omitempty" yaml:"bearer,omitempty"`
+	Basic  *BasicAuth  `json:"basic,omitempty" yaml:"basic,omitempty"`
+	OAuth2 *OAuth2Auth `json:"oauth2,omitempty" yaml:"oauth2,omitempty"`
+	MTLS   *MTLSAuth   `json:"mtls,omitempty" yaml:"mtls,omitempty"`
+}
+
+// SecretSource references a secret by environment variable or file. Exactly one must be set.
+type SecretSource struct {
+	Env  string `json:"env,omitempty" yaml:"env,omitempty"`
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+// BearerAuth sends the token in an "Authorization: Bearer" header
+type BearerAuth struct {
+	Token SecretSource `json:"token" yaml:"token"`
+}
+
+// BasicAuth sends the username and password using HTTP basic authentication
+type BasicAuth struct {
+	Username string       `json:"username" yaml:"username"`
+	Password SecretSource `json:"password" yaml:"password"`
+}
+
+// OAuth2Auth fetches a token using the OAuth2 client credentials flow. The
+// token is cached and reused across the requests sharing the options until it
+// expires.
+type OAuth2Auth struct {
+	TokenURL     string       `json:"token-url" yaml:"token-url"`
+	ClientID     string       `json:"client-id" yaml:"client-id"`
+	ClientSecret SecretSource `json:"client-secret" yaml:"client-secret"`
+	Scopes       []string     `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// MTLSAuth configures a client certificate, and optionally a CA to verify the server with
+type MTLSAuth struct {
+	CertFile string `json:"cert-file,omitempty" yaml:"cert-file,omitempty"`
+	KeyFile  string `json:"key-file,omitempty" yaml:"key-file,omitempty"`
+	CAFile   string `json:"ca-file,omitempty" yaml:"ca-file,omitempty"`
+}
+
+// authorizer adds authentication to a request
+type authorizer func(*http.Request) error
+
+func validateAuth(auth *AuthOpts) (errs error) {
+	schemes := 0
+	if auth.Bearer != nil {
+		schemes++
+		if err := auth.Bearer.Token.validate(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("bearer token: %w", err))
+		}
+	}
+	if auth.Basic != nil {
+		schemes++
+		if auth.Basic.Username == "" {
+			errs = errors.Join(errs, errors.New("basic auth username cannot be empty"))
+		}
+		if err := auth.Basic.Password.validate(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("basic auth password: %w", err))
+		}
+	}
+	if auth.OAuth2 != nil {
+		schemes++
+		if auth.OAuth2.TokenURL == "" {
+			errs = errors.Join(errs, errors.New("oauth2 token-url cannot be empty"))
+		} else if _, err := url.Parse(auth.OAuth2.TokenURL); err != nil {
+			errs = errors.Join(errs, errors.New("invalid oauth2 token-url"))
+		}
+		if auth.OAuth2.ClientID == "" {
+			errs = errors.Join(errs, errors.New("oauth2 client-id cannot be empty"))
+		}
+		if err := auth.OAuth2.ClientSecret.validate(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("oauth2 client-secret: %w", err))
+		}
+	}
+	if schemes > 1 {
+		errs = errors.Join(errs, errors.New("only one of bearer, basic and oauth2 auth may be specified"))
+	}
+	if auth.MTLS != nil {
+		if (auth.MTLS.CertFile == "") != (auth.MTLS.KeyFile == "") {
+			errs = errors.Join(errs, errors.New("mtls cert-file and key-file must be specified together"))
+		}
+		if auth.MTLS.CertFile == "" && auth.MTLS.CAFile == "" {
+			errs = errors.Join(errs, errors.New("mtls requires a cert-file and key-file, or a ca-file"))
+		}
+	}
+	return errs
+}
+
+func (s SecretSource) validate() error {
+	if (s.Env == "") == (s.File == "") {
+		return errors.New("exactly one of env or file must be specified")
+	}
+	return nil
+}
+
+// resolve reads the secret value. Errors never include the value itself.
+func (s SecretSource) resolve(workDir string) (string, error) {
+	if s.Env != "" {
+		value, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	}
+
+	b, err := os.ReadFile(resolvePath(s.File, workDir))
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// tlsConfigFromAuth creates the TLS config for mTLS, or nil if it is not configured
+func tlsConfigFromAuth(auth *AuthOpts, workDir string) (*tls.Config, error) {
+	if auth == nil || auth.MTLS == nil {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if auth.MTLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(resolvePath(auth.MTLS.CertFile, workDir), resolvePath(auth.MTLS.KeyFile, workDir))
+		if err != nil {
+			return nil, fmt.Errorf("error loading mtls client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if auth.MTLS.CAFile != "" {
+		ca, err := os.ReadFile(resolvePath(auth.MTLS.CAFile, workDir))
+		if err != nil {
+			return nil, fmt.Errorf("error reading mtls ca-file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("mtls ca-file does not contain any PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// authorizerFromOpts resolves the configured secrets and returns a function
+// that adds authentication to requests. The client is used to fetch OAuth2
+// tokens, which are reused by the requests sharing the authorizer.
+func authorizerFromOpts(ctx context.Context, opts *ApiOpts, client http.Client, workDir string) (authorizer, error) {
+	if opts == nil || opts.Auth == nil {
+		return nil, nil
+	}
+	auth := opts.Auth
+
+	switch {
+	case auth.Bearer != nil:
+		token, err := auth.Bearer.Token.resolve(workDir)
+		if err != nil {
+			return nil, fmt.Errorf("bearer token: %w", err)
+		}
+		return func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		}, nil
+	case auth.Basic != nil:
+		password, err := auth.Basic.Password.resolve(workDir)
+		if err != nil {
+			return nil, fmt.Errorf("basic auth password: %w", err)
+		}
+		username := auth.Basic.Username
+		return func(req *http.Request) error {
+			req.SetBasicAuth(username, password)
+			return nil
+		}, nil
+	case auth.OAuth2 != nil:
+		secret, err := auth.OAuth2.ClientSecret.resolve(workDir)
+		if err != nil {
+			return nil, fmt.Errorf("oauth2 client-secret: %w", err)
+		}
+		cfg := clientcredentials.Config{
+			ClientID:     auth.OAuth2.ClientID,
+			ClientSecret: secret,
+			TokenURL:     auth.OAuth2.TokenURL,
+			Scopes:       auth.OAuth2.Scopes,
+		}
+		tokenSource := cfg.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, &client))
+		return func(req *http.Request) error {
+			token, err := tokenSource.Token()
+			if err != nil {
+				return fmt.Errorf("error fetching oauth2 token: %w", err)
+			}
+			token.SetAuthHeader(req)
+			return nil
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// resolvePath resolves relative paths from the working directory
+func resolvePath(path, workDir string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(workDir, path)
+}
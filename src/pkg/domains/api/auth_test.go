@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+const testSecret = "super-secret-value"
+
+func TestValidateAuth(t *testing.T) {
+	tests := map[string]struct {
+		auth    *AuthOpts
+		wantErr bool
+	}{
+		"bearer from env": {
+			auth: &AuthOpts{Bearer: &BearerAuth{Token: SecretSource{Env: "TOKEN"}}},
+		},
+		"bearer without source": {
+			auth:    &AuthOpts{Bearer: &BearerAuth{}},
+			wantErr: true,
+		},
+		"bearer with env and file": {
+			auth:    &AuthOpts{Bearer: &BearerAuth{Token: SecretSource{Env: "TOKEN", File: "token"}}},
+			wantErr: true,
+		},
+		"basic without username": {
+			auth:    &AuthOpts{Basic: &BasicAuth{Password: SecretSource{File: "password"}}},
+			wantErr: true,
+		},
+		"multiple schemes": {
+			auth: &AuthOpts{
+				Bearer: &BearerAuth{Token: SecretSource{Env: "TOKEN"}},
+				Basic:  &BasicAuth{Username: "user", Password: SecretSource{Env: "PASSWORD"}},
+			},
+			wantErr: true,
+		},
+		"oauth2 missing fields": {
+			auth:    &AuthOpts{OAuth2: &OAuth2Auth{ClientSecret: SecretSource{Env: "SECRET"}}},
+			wantErr: true,
+		},
+		"mtls with bearer": {
+			auth: &AuthOpts{
+				Bearer: &BearerAuth{Token: SecretSource{Env: "TOKEN"}},
+				MTLS:   &MTLSAuth{CertFile: "tls.crt", KeyFile: "tls.key"},
+			},
+		},
+		"mtls cert without key": {
+			auth:    &AuthOpts{MTLS: &MTLSAuth{CertFile: "tls.crt"}},
+			wantErr: true,
+		},
+		"mtls ca only": {
+			auth: &AuthOpts{MTLS: &MTLSAuth{CAFile: "ca.crt"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAuth(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetResourcesWithAuth(t *testing.T) {
+	var tokenFetches atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenFetches.Add(1)
+			if id, secret, ok := r.BasicAuth(); !ok || id != "lula" || secret != testSecret {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "oauth-token", "token_type": "bearer", "expires_in": 3600}`))
+		case "/bearer":
+			writeAuthorized(w, r.Header.Get("Authorization") == "Bearer "+testSecret)
+		case "/basic":
+			user, password, ok := r.BasicAuth()
+			writeAuthorized(w, ok && user == "lula" && password == testSecret)
+		case "/oauth2":
+			writeAuthorized(w, r.Header.Get("Authorization") == "Bearer oauth-token")
+		}
+	}))
+	defer svr.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte(testSecret+"\n"), 0600))
+	t.Setenv("LULA_TEST_SECRET", testSecret)
+	ctx := context.WithValue(context.Background(), types.LulaValidationWorkDir, dir)
+
+	tests := map[string]struct {
+		path string
+		auth *AuthOpts
+	}{
+		"bearer from env": {
+			path: "/bearer",
+			auth: &AuthOpts{Bearer: &BearerAuth{Token: SecretSource{Env: "LULA_TEST_SECRET"}}},
+		},
+		"bearer from file": {
+			path: "/bearer",
+			auth: &AuthOpts{Bearer: &BearerAuth{Token: SecretSource{File: "token"}}},
+		},
+		"basic": {
+			path: "/basic",
+			auth: &AuthOpts{Basic: &BasicAuth{Username: "lula", Password: SecretSource{Env: "LULA_TEST_SECRET"}}},
+		},
+		"oauth2 client credentials": {
+			path: "/oauth2",
+			auth: &AuthOpts{OAuth2: &OAuth2Auth{
+				TokenURL:     svr.URL + "/token",
+				ClientID:     "lula",
+				ClientSecret: SecretSource{File: "token"},
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tokenFetches.Store(0)
+			api, err := CreateApiDomain(&ApiSpec{
+				Requests: []Request{
+					{Name: "first", URL: svr.URL + tt.path},
+					{Name: "second", URL: svr.URL + tt.path},
+				},
+				Options: &ApiOpts{Auth: tt.auth},
+			})
+			require.NoError(t, err)
+
+			drs, err := api.GetResources(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 200, drs["first"].(types.DomainResources)["statuscode"])
+			require.Equal(t, 200, drs["second"].(types.DomainResources)["statuscode"])
+
+			// secrets must never end up in the resources
+			b, err := json.Marshal(drs)
+			require.NoError(t, err)
+			require.NotContains(t, string(b), testSecret)
+
+			if tt.auth.OAuth2 != nil {
+				// the token is cached across requests
+				require.Equal(t, int32(1), tokenFetches.Load())
+			}
+		})
+	}
+
+	t.Run("missing secret", func(t *testing.T) {
+		api, err := CreateApiDomain(&ApiSpec{
+			Requests: []Request{{Name: "bearer", URL: svr.URL + "/bearer"}},
+			Options:  &ApiOpts{Auth: &AuthOpts{Bearer: &BearerAuth{Token: SecretSource{Env: "LULA_TEST_UNSET"}}}},
+		})
+		require.NoError(t, err)
+
+		drs, err := api.GetResources(ctx)
+		require.ErrorContains(t, err, "LULA_TEST_UNSET is not set")
+		require.Equal(t, types.DomainResources{"bearer": types.DomainResources{"status": 0}}, drs)
+	})
+
+	t.Run("oauth2 shared by requests with different clients", func(t *testing.T) {
+		// the proxy serves the requests sent through it, recording the tokens fetched
+		var proxiedTokenFetches atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				proxiedTokenFetches.Add(1)
+			}
+			svr.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer proxy.Close()
+
+		tokenFetches.Store(0)
+		auth := &AuthOpts{OAuth2: &OAuth2Auth{
+			TokenURL:     svr.URL + "/token",
+			ClientID:     "lula",
+			ClientSecret: SecretSource{File: "token"},
+		}}
+		spec := &ApiSpec{
+			Requests: []Request{
+				{Name: "direct", URL: svr.URL + "/oauth2"},
+				{Name: "proxied", URL: svr.URL + "/oauth2", Options: &ApiOpts{Proxy: proxy.URL, Auth: auth}},
+			},
+			Options: &ApiOpts{Auth: auth},
+		}
+		api, err := CreateApiDomain(spec)
+		require.NoError(t, err)
+
+		for range 2 {
+			drs, err := api.GetResources(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 200, drs["direct"].(types.DomainResources)["statuscode"])
+			require.Equal(t, 200, drs["proxied"].(types.DomainResources)["statuscode"])
+		}
+		// each request fetches its token through its own client
+		require.Equal(t, int32(4), tokenFetches.Load())
+		require.Equal(t, int32(2), proxiedTokenFetches.Load())
+		require.Equal(t, &OAuth2Auth{TokenURL: svr.URL + "/token", ClientID: "lula", ClientSecret: SecretSource{File: "token"}}, auth.OAuth2)
+	})
+}
+
+func TestGetResourcesWithMTLS(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := writeTestCert(t, dir, "ca", nil, nil)
+	writeTestCert(t, dir, "client", caCert, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAuthorized(w, len(r.TLS.PeerCertificates) > 0)
+	}))
+	svr.TLS = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert, MinVersion: tls.VersionTLS12}
+	svr.StartTLS()
+	defer svr.Close()
+
+	// trust the test server certificate
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server-ca.crt"), serverCA, 0600))
+
+	ctx := context.WithValue(context.Background(), types.LulaValidationWorkDir, dir)
+
+	t.Run("with client certificate", func(t *testing.T) {
+		api, err := CreateApiDomain(&ApiSpec{
+			Requests: []Request{{Name: "mtls", URL: svr.URL}},
+			Options: &ApiOpts{Auth: &AuthOpts{MTLS: &MTLSAuth{
+				CertFile: "client.crt",
+				KeyFile:  "client.key",
+				CAFile:   "server-ca.crt",
+			}}},
+		})
+		require.NoError(t, err)
+
+		drs, err := api.GetResources(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 200, drs["mtls"].(types.DomainResources)["statuscode"])
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		api, err := CreateApiDomain(&ApiSpec{
+			Requests: []Request{{Name: "mtls", URL: svr.URL}},
+			Options:  &ApiOpts{Auth: &AuthOpts{MTLS: &MTLSAuth{CAFile: "server-ca.crt"}}},
+		})
+		require.NoError(t, err)
+
+		_, err = api.GetResources(ctx)
+		require.Error(t, err)
+	})
+}
+
+func writeAuthorized(w http.ResponseWriter, authorized bool) {
+	if !authorized {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_, _ = w.Write([]byte(`{"authorized": true}`))
+}
+
+// writeTestCert writes a PEM certificate and key named <name>.crt and <name>.key
+// to dir. The certificate is self-signed CA if parent is nil.
+func writeTestCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent, parentKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return cert, key
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+// referencePattern matches references to the resources of other requests, e.g.
+// ${login.response.token}. The first segment is the request name, the rest is
+// a dot-separated path into its resource.
+var referencePattern = regexp.MustCompile(`\$\{([^${}\s]+)\}`)
+
+// forEachItem is the name used to reference the current element in requests
+// with a for-each.
+const forEachItem = "item"
+
+var ErrRequestCycle = errors.New("request dependency cycle")
+
+// references returns the names of the requests referenced by the request, in
+// the order they appear. References to unknown names are not included.
+func (r Request) references(names map[string]bool) []string {
+	var refs []string
+	for _, s := range r.templates() {
+		for _, match := range referencePattern.FindAllStringSubmatch(s, -1) {
+			name, _, _ := strings.Cut(match[1], ".")
+			if names[name] && !slices.Contains(refs, name) {
+				refs = append(refs, name)
+			}
+		}
+	}
+	return refs
+}
+
+// templates returns every field of the request that may contain references
+func (r Request) templates() []string {
+	templates := []string{r.URL, r.Body, r.ForEach}
+	for _, v := range r.Params {
+		templates = append(templates, v)
+	}
+	if r.Options != nil {
+		for _, v := range r.Options.Headers {
+			templates = append(templates, v)
+		}
+	}
+	return templates
+}
+
+// orderRequests returns the indexes of the requests in an order where every
+// request comes after the requests it references. Requests without dependencies
+// between them keep the order they are specified in.
+func orderRequests(requests []Request) ([]int, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[string]bool, len(requests))
+	for _, r := range requests {
+		names[r.Name] = true
+	}
+
+	deps := make([][]string, len(requests))
+	for i, r := range requests {
+		deps[i] = r.references(names)
+	}
+
+	order := make([]int, 0, len(requests))
+	done := make(map[string]bool, len(requests))
+	for len(order) < len(requests) {
+		progress := false
+		for i, r := range requests {
+			if done[r.Name] || slices.ContainsFunc(deps[i], func(dep string) bool { return !done[dep] }) {
+				continue
+			}
+			order = append(order, i)
+			done[r.Name] = true
+			progress = true
+			// restart so earlier requests unblocked by this one keep their place
+			break
+		}
+		if !progress {
+			var cycle []string
+			for _, r := range requests {
+				if !done[r.Name] {
+					cycle = append(cycle, r.Name)
+				}
+			}
+			return nil, fmt.Errorf("%w between requests %s", ErrRequestCycle, strings.Join(cycle, ", "))
+		}
+	}
+
+	return order, nil
+}
+
+// render replaces the references in s with values from the resources of
+// earlier requests. In a for-each request, item is the current element.
+// References to unknown names are left as-is.
+func render(s string, resources types.DomainResources, names map[string]bool, item any, hasItem bool) (string, error) {
+	var errs error
+	rendered := referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		v, ok, err := resolveReference(match[2:len(match)-1], resources, names, item, hasItem)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			return match
+		}
+		if !ok {
+			return match
+		}
+		return stringify(v)
+	})
+	return rendered, errs
+}
+
+// resolveReference returns the value of a reference, or ok false if it
+// doesn't reference a request.
+func resolveReference(ref string, resources types.DomainResources, names map[string]bool, item any, hasItem bool) (v any, ok bool, err error) {
+	name, path, _ := strings.Cut(ref, ".")
+	switch {
+	case hasItem && name == forEachItem:
+		v = item
+	case names[name]:
+		if v, ok = resources[name]; !ok {
+			return nil, true, fmt.Errorf("request %s has not been made", name)
+		}
+	default:
+		return nil, false, nil
+	}
+
+	if path == "" {
+		return v, true, nil
+	}
+	v, err = lookupReference(v, strings.Split(path, "."))
+	if err != nil {
+		return nil, true, fmt.Errorf("error resolving ${%s}: %w", ref, err)
+	}
+	return v, true, nil
+}
+
+// lookupReference walks the path through maps and arrays
+func lookupReference(v any, path []string) (any, error) {
+	for _, key := range path {
+		switch current := v.(type) {
+		case types.DomainResources:
+			next, ok := current[key]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", key)
+			}
+			v = next
+		case map[string]any:
+			next, ok := current[key]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", key)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(current) {
+				return nil, fmt.Errorf("index %q out of range", key)
+			}
+			v = current[i]
+		default:
+			return nil, fmt.Errorf("%q not found", key)
+		}
+	}
+	return v, nil
+}
+
+// stringify formats a value for use in a request. Objects and arrays are
+// formatted as JSON.
+func stringify(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(value)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(b)
+	}
+}
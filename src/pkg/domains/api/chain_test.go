@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestOrderRequests(t *testing.T) {
+	tests := map[string]struct {
+		requests []Request
+		want     []int
+		wantErr  bool
+	}{
+		"no references keep their order": {
+			requests: []Request{{Name: "a", URL: "http://a"}, {Name: "b", URL: "http://b"}},
+			want:     []int{0, 1},
+		},
+		"referenced requests come first": {
+			requests: []Request{
+				{Name: "items", URL: "http://example.com/items", Options: &ApiOpts{Headers: map[string]string{"Authorization": "Bearer ${login.response.token}"}}},
+				{Name: "health", URL: "http://example.com/health"},
+				{Name: "login", URL: "http://example.com/login"},
+			},
+			want: []int{1, 2, 0},
+		},
+		"unknown names are not references": {
+			requests: []Request{{Name: "a", URL: "http://a", Body: `{"home": "${HOME}"}`}},
+			want:     []int{0},
+		},
+		"self reference": {
+			requests: []Request{{Name: "a", URL: "http://a/${a.response.id}"}},
+			wantErr:  true,
+		},
+		"cycle": {
+			requests: []Request{
+				{Name: "a", URL: "http://a", Params: map[string]string{"id": "${b.response.id}"}},
+				{Name: "b", URL: "http://b", Body: "${a.response}"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := orderRequests(tt.requests)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrRequestCycle)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateApiDomainWithReferences(t *testing.T) {
+	tests := map[string]*ApiSpec{
+		"cycle": {Requests: []Request{
+			{Name: "a", URL: "http://a/${b.response.id}"},
+			{Name: "b", URL: "http://b/${a.response.id}"},
+		}},
+		"duplicate names": {Requests: []Request{
+			{Name: "a", URL: "http://a"},
+			{Name: "a", URL: "http://b"},
+		}},
+		"for-each is not a reference": {Requests: []Request{
+			{Name: "a", URL: "http://a"},
+			{Name: "b", URL: "http://b", ForEach: "a.response"},
+		}},
+		"for-each references unknown request": {Requests: []Request{
+			{Name: "b", URL: "http://b", ForEach: "${a.response}"},
+		}},
+		"reference in top-level headers": {
+			Requests: []Request{{Name: "a", URL: "http://a"}},
+			Options:  &ApiOpts{Headers: map[string]string{"Authorization": "${a.response.token}"}},
+		},
+	}
+
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CreateApiDomain(spec)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestGetResourcesWithReferences(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/login":
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			if creds["user"] != "lula" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeJSON(w, map[string]any{"token": "session-token", "user": map[string]any{"id": 42}})
+		case r.Header.Get("Authorization") != "Bearer session-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/users/42/cats":
+			writeJSON(w, []any{map[string]any{"id": "cheetarah"}, map[string]any{"id": "snakob"}})
+		case strings.HasPrefix(r.URL.Path, "/cats/"):
+			writeJSON(w, map[string]any{"name": strings.TrimPrefix(r.URL.Path, "/cats/"), "fast": r.URL.Query().Get("fast")})
+		}
+	}))
+	defer svr.Close()
+
+	auth := &ApiOpts{Headers: map[string]string{"Authorization": "Bearer ${login.response.token}"}}
+	api, err := CreateApiDomain(&ApiSpec{Requests: []Request{
+		// listed before the requests they depend on
+		{
+			Name:    "cat",
+			URL:     svr.URL + "/cats/${item.id}",
+			Params:  map[string]string{"fast": "${item.id}"},
+			ForEach: "${cats.response}",
+			Options: auth,
+		},
+		{Name: "cats", URL: svr.URL + "/users/${login.response.user.id}/cats", Options: auth},
+		{Name: "login", URL: svr.URL + "/login", Method: "post", Body: `{"user": "lula"}`},
+	}})
+	require.NoError(t, err)
+
+	drs, err := api.GetResources(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 200, drs["cats"].(types.DomainResources)["statuscode"])
+	cats := drs["cat"].([]interface{})
+	require.Len(t, cats, 2)
+	first := cats[0].(types.DomainResources)
+	require.Equal(t, map[string]any{"id": "cheetarah"}, first["item"])
+	require.Equal(t, map[string]any{"name": "cheetarah", "fast": "cheetarah"}, first["response"])
+	require.Equal(t, "snakob", cats[1].(types.DomainResources)["response"].(map[string]any)["name"])
+
+	t.Run("missing value", func(t *testing.T) {
+		api, err := CreateApiDomain(&ApiSpec{Requests: []Request{
+			{Name: "login", URL: svr.URL + "/login", Method: "post", Body: `{"user": "lula"}`},
+			{Name: "cats", URL: svr.URL + "/users/${login.response.missing}/cats"},
+		}})
+		require.NoError(t, err)
+
+		drs, err := api.GetResources(context.Background())
+		require.ErrorContains(t, err, "missing")
+		require.Equal(t, types.DomainResources{"status": 0}, drs["cats"])
+	})
+}
@@ -2,41 +2,74 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/defenseunicorns/lula/src/pkg/message"
 )
 
-func doHTTPReq(ctx context.Context, client http.Client, method string, url url.URL, body io.Reader, headers map[string]string, queryParameters url.Values) (*APIResponse, error) {
+// httpRequest contains everything needed to send a request, so it can be
+// sent multiple times for retries and pagination.
+type httpRequest struct {
+	client    http.Client
+	method    string
+	url       url.URL
+	body      string
+	headers   map[string]string
+	params    url.Values
+	authorize authorizer
+}
+
+func doHTTPReq(ctx context.Context, r httpRequest) (*APIResponse, error) {
+	url := r.url
 	// append any query parameters
 	q := url.Query()
-	for k, v := range queryParameters {
+	for k, v := range r.params {
 		// using Add instead of set in case the input URL already had a query encoded
 		q.Add(k, strings.Join(v, ","))
 	}
 	// set the query to the encoded parameters
 	url.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, method, url.String(), body)
+	var body io.Reader
+	if r.body != "" {
+		body = strings.NewReader(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, url.String(), body)
 	if err != nil {
 		message.Debugf("error from http.NewRequestWithContext: %s", err)
 		return nil, err
 	}
 	// add each header to the request
-	for k, v := range headers {
+	for k, v := range r.headers {
 		req.Header.Set(k, v)
 	}
+	// add authentication after the headers, so it can't be overridden
+	if r.authorize != nil {
+		if err := r.authorize(req); err != nil {
+			message.Debugf("error authorizing request: %s", err)
+			return nil, err
+		}
+	}
 
 	// log the request
-	message.Debugf("%q %s", method, req.URL.Redacted())
+	message.Debugf("%q %s", r.method, req.URL.Redacted())
+
+	var respObj APIResponse
+	trace := newTimingTrace(&respObj.Timing)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
 
 	// do the thing
-	res, err := client.Do(req)
+	res, err := r.client.Do(req)
 	if err != nil {
 		message.Debugf("error from client.Do: %s", err)
 		return nil, err
@@ -46,8 +79,10 @@ func doHTTPReq(ctx context.Context, client http.Client, method string, url url.U
 		return nil, fmt.Errorf("error: %s returned empty response", url.Redacted())
 	}
 	defer res.Body.Close()
-	var respObj APIResponse
+	trace.done()
 	respObj.StatusCode = res.StatusCode
+	respObj.Headers = res.Header
+	respObj.TLS = res.TLS
 	if res.Status == "" {
 		respObj.Status = http.StatusText(res.StatusCode)
 	} else {
@@ -59,7 +94,8 @@ func doHTTPReq(ctx context.Context, client http.Client, method string, url url.U
 		return nil, err
 	}
 
-	if respObj.StatusCode >= http.StatusOK && respObj.StatusCode < http.StatusMultiStatus {
+	// responses to HEAD requests, 204s etc. have no body to parse
+	if respObj.StatusCode >= http.StatusOK && respObj.StatusCode < http.StatusMultiStatus && len(responseData) > 0 {
 		respObj.Raw = responseData
 		err = json.Unmarshal(responseData, &respObj.Response)
 		if err != nil {
@@ -70,14 +106,76 @@ func doHTTPReq(ctx context.Context, client http.Client, method string, url url.U
 	return &respObj, nil
 }
 
-func clientFromOpts(opts *ApiOpts) http.Client {
+func clientFromOpts(opts *ApiOpts, workDir string) (http.Client, error) {
 	transport := &http.Transport{}
 	if opts.proxyURL != nil {
 		transport.Proxy = http.ProxyURL(opts.proxyURL)
 	}
+	tlsConfig, err := tlsConfigFromAuth(opts.Auth, workDir)
+	if err != nil {
+		return http.Client{}, err
+	}
+	transport.TLSClientConfig = tlsConfig
 	c := http.Client{Transport: transport}
 	if opts.timeout != nil {
 		c.Timeout = *opts.timeout
 	}
-	return c
+	return c, nil
+}
+
+// timingTrace records the timing of the phases of a request. The trace
+// callbacks may be called concurrently, e.g. when dialing multiple addresses.
+type timingTrace struct {
+	mu     sync.Mutex
+	timing *Timing
+	start  time.Time
+	dns    time.Time
+	dial   time.Time
+	tls    time.Time
+	closed bool
+}
+
+func newTimingTrace(timing *Timing) *timingTrace {
+	return &timingTrace{timing: timing, start: time.Now()}
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(func() { t.dns = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(func() { t.timing.DNS = time.Since(t.dns) }) },
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				if t.dial.IsZero() {
+					t.dial = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.record(func() {
+				if err == nil {
+					t.timing.Connect = time.Since(t.dial)
+				}
+			})
+		},
+		TLSHandshakeStart:    func() { t.record(func() { t.tls = time.Now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.record(func() { t.timing.TLSHandshake = time.Since(t.tls) }) },
+		GotFirstResponseByte: func() { t.record(func() { t.timing.FirstByte = time.Since(t.start) }) },
+	}
+}
+
+// record runs fn while holding the lock, unless the trace is done
+func (t *timingTrace) record(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		fn()
+	}
+}
+
+// done stops recording, so late callbacks from abandoned dials don't race
+// with reads of the timing.
+func (t *timingTrace) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
 }
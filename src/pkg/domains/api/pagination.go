@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/defenseunicorns/lula/src/pkg/message"
+)
+
+var ErrTooManyPages = errors.New("too many pages")
+
+// doPaginatedReq fetches every page of results and merges the items of each
+// page into a single response array. The status of the returned response is
+// that of the last page fetched.
+func doPaginatedReq(ctx context.Context, r httpRequest, retry *RetryOpts, p *Pagination) (*APIResponse, error) {
+	items := make([]any, 0)
+	attempts := 0
+	offset := 0
+	r.params = cloneValues(r.params)
+	if p.Type == PaginationOffset {
+		r.params.Set(p.OffsetParam, "0")
+		r.params.Set(p.LimitParam, strconv.Itoa(p.Limit))
+	}
+
+	for page := 1; ; page++ {
+		response, err := doHTTPReqWithRetry(ctx, r, retry)
+		if response == nil {
+			return nil, err
+		}
+		attempts += response.Attempts
+		response.Attempts = attempts
+		response.Pages = page
+		if err != nil {
+			return response, err
+		}
+		if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultiStatus {
+			if page > 1 {
+				return response, fmt.Errorf("page %d of %s returned %s", page, r.url.Redacted(), response.Status)
+			}
+			return response, nil
+		}
+
+		pageItems, err := pageItems(response.Response, p.Items)
+		if err != nil {
+			return response, fmt.Errorf("page %d: %w", page, err)
+		}
+		items = append(items, pageItems...)
+
+		// determine the next page, if any
+		more := false
+		switch p.Type {
+		case PaginationLink:
+			next, err := nextLink(response.Headers, &r.url)
+			if err != nil {
+				return response, fmt.Errorf("page %d: %w", page, err)
+			}
+			if next != nil {
+				// the next link includes any query parameters
+				r.url, r.params = *next, nil
+				more = true
+			}
+		case PaginationCursor:
+			cursor, err := cursorValue(response.Response, p.CursorField)
+			if err != nil {
+				return response, fmt.Errorf("page %d: %w", page, err)
+			}
+			if cursor != "" {
+				r.params.Set(p.CursorParam, cursor)
+				more = true
+			}
+		case PaginationOffset:
+			if len(pageItems) >= p.Limit {
+				offset += len(pageItems)
+				r.params.Set(p.OffsetParam, strconv.Itoa(offset))
+				more = true
+			}
+		}
+
+		if !more {
+			return mergedResponse(response, items)
+		}
+		if page >= p.MaxPages {
+			merged, err := mergedResponse(response, items)
+			return merged, errors.Join(err, fmt.Errorf("%w: %s has more than %d pages", ErrTooManyPages, r.url.Redacted(), p.MaxPages))
+		}
+		message.Debugf("fetching page %d of %s", page+1, r.url.Redacted())
+	}
+}
+
+// mergedResponse replaces the response body with the merged items
+func mergedResponse(response *APIResponse, items []any) (*APIResponse, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return response, err
+	}
+	response.Raw = raw
+	response.Response = items
+	return response, nil
+}
+
+// pageItems returns the array found at the dot-separated path in the response
+func pageItems(response any, path string) ([]any, error) {
+	v, err := lookupPath(response, path)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := v.([]any)
+	if !ok {
+		if path == "" {
+			return nil, errors.New("paginated response is not an array, set pagination items to the path of the results")
+		}
+		return nil, fmt.Errorf("pagination items %q is not an array", path)
+	}
+	return items, nil
+}
+
+// cursorValue returns the cursor found at the dot-separated path in the
+// response, or an empty string if there are no more pages.
+func cursorValue(response any, path string) (string, error) {
+	v, err := lookupPath(response, path)
+	if err != nil {
+		// a missing cursor means there are no more pages
+		return "", nil
+	}
+	switch cursor := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return cursor, nil
+	case float64:
+		return strconv.FormatFloat(cursor, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("pagination cursor-field %q is not a string or number", path)
+	}
+}
+
+// lookupPath returns the value at the dot-separated path of map keys, or the
+// value itself if the path is empty.
+func lookupPath(v any, path string) (any, error) {
+	if path == "" {
+		return v, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%q not found in response", path)
+		}
+		if v, ok = m[key]; !ok {
+			return nil, fmt.Errorf("%q not found in response", path)
+		}
+	}
+	return v, nil
+}
+
+// nextLink returns the rel="next" URL from the Link headers, resolved against
+// the current URL, or nil if there isn't one.
+func nextLink(headers http.Header, current *url.URL) (*url.URL, error) {
+	for _, header := range headers.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range segments[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						next, err := current.Parse(strings.Trim(target, "<>"))
+						if err != nil {
+							return nil, fmt.Errorf("invalid next link: %w", err)
+						}
+						return next, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := url.Values{}
+	for k, v := range values {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestValidateAndMutatePagination(t *testing.T) {
+	tests := map[string]struct {
+		input, want *Pagination
+		wantErr     bool
+	}{
+		"link defaults": {
+			input: &Pagination{Type: PaginationLink},
+			want:  &Pagination{Type: PaginationLink, MaxPages: defaultMaxPages},
+		},
+		"offset defaults": {
+			input: &Pagination{Type: PaginationOffset},
+			want:  &Pagination{Type: PaginationOffset, OffsetParam: "offset", LimitParam: "limit", Limit: defaultPageLimit, MaxPages: defaultMaxPages},
+		},
+		"cursor without field": {
+			input:   &Pagination{Type: PaginationCursor, CursorParam: "after"},
+			wantErr: true,
+		},
+		"invalid type": {
+			input:   &Pagination{Type: "pages"},
+			wantErr: true,
+		},
+		"negative max-pages": {
+			input:   &Pagination{Type: PaginationLink, MaxPages: -1},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAndMutatePagination(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tt.input)
+		})
+	}
+}
+
+func TestGetResourcesWithPagination(t *testing.T) {
+	// 5 items, served 2 per page
+	items := []string{"Cheetarah", "Li Shou", "Snakob", "Lion-O", "Tygra"}
+	page := func(start int) []any {
+		end := min(start+2, len(items))
+		out := make([]any, 0, end-start)
+		for _, item := range items[start:end] {
+			out = append(out, item)
+		}
+		return out
+	}
+	want := []any{"Cheetarah", "Li Shou", "Snakob", "Lion-O", "Tygra"}
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		switch r.URL.Path {
+		case "/link":
+			if start+2 < len(items) {
+				w.Header().Set("Link", fmt.Sprintf(`</link?start=%d>; rel="next", </link?start=0>; rel="first"`, start+2))
+			}
+			writeJSON(w, page(start))
+		case "/cursor":
+			next := any(nil)
+			if start+2 < len(items) {
+				next = strconv.Itoa(start + 2)
+			}
+			writeJSON(w, map[string]any{"data": map[string]any{"items": page(start), "next": next}})
+		case "/offset":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("first"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("max"))
+			end := min(offset+limit, len(items))
+			out := make([]any, 0)
+			for _, item := range items[min(offset, end):end] {
+				out = append(out, item)
+			}
+			writeJSON(w, out)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer svr.Close()
+
+	tests := map[string]struct {
+		request   Request
+		wantItems []any
+		wantPages int
+		wantErr   bool
+	}{
+		"link header": {
+			request:   Request{Name: "test", URL: svr.URL + "/link", Pagination: &Pagination{Type: PaginationLink}},
+			wantItems: want,
+			wantPages: 3,
+		},
+		"cursor field": {
+			request: Request{Name: "test", URL: svr.URL + "/cursor", Pagination: &Pagination{
+				Type:        PaginationCursor,
+				Items:       "data.items",
+				CursorField: "data.next",
+				CursorParam: "start",
+			}},
+			wantItems: want,
+			wantPages: 3,
+		},
+		"offset and limit": {
+			request: Request{Name: "test", URL: svr.URL + "/offset", Pagination: &Pagination{
+				Type:        PaginationOffset,
+				OffsetParam: "first",
+				LimitParam:  "max",
+				Limit:       2,
+			}},
+			wantItems: want,
+			wantPages: 3,
+		},
+		"max pages exceeded": {
+			request:   Request{Name: "test", URL: svr.URL + "/link", Pagination: &Pagination{Type: PaginationLink, MaxPages: 2}},
+			wantItems: []any{"Cheetarah", "Li Shou", "Snakob", "Lion-O"},
+			wantPages: 2,
+			wantErr:   true,
+		},
+		"items not an array": {
+			request:   Request{Name: "test", URL: svr.URL + "/cursor", Pagination: &Pagination{Type: PaginationCursor, CursorField: "data.next", CursorParam: "start"}},
+			wantPages: 1,
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api, err := CreateApiDomain(&ApiSpec{Requests: []Request{tt.request}})
+			require.NoError(t, err)
+
+			drs, err := api.GetResources(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			dr := drs["test"].(types.DomainResources)
+			require.Equal(t, tt.wantPages, dr["pages"])
+			if tt.wantItems != nil {
+				require.Equal(t, tt.wantItems, dr["response"])
+			}
+		})
+	}
+
+	t.Run("error status on first page", func(t *testing.T) {
+		api, err := CreateApiDomain(&ApiSpec{Requests: []Request{
+			{Name: "test", URL: svr.URL + "/forbidden", Pagination: &Pagination{Type: PaginationLink}},
+		}})
+		require.NoError(t, err)
+
+		drs, err := api.GetResources(context.Background())
+		require.NoError(t, err)
+		dr := drs["test"].(types.DomainResources)
+		require.Equal(t, http.StatusForbidden, dr["statuscode"])
+		require.Equal(t, 1, dr["pages"])
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
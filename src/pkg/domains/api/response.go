@@ -0,0 +1,72 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// headersResource formats the response headers for the domain resources. Header
+// names are lowercased, and multiple values for the same header are joined
+// with ", ".
+func headersResource(headers http.Header) map[string]interface{} {
+	resource := make(map[string]interface{}, len(headers))
+	for k, v := range headers {
+		resource[strings.ToLower(k)] = strings.Join(v, ", ")
+	}
+	return resource
+}
+
+// tlsResource formats the TLS connection state for the domain resources, or
+// returns nil if the connection didn't use TLS.
+func tlsResource(state *tls.ConnectionState) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+
+	certificates := make([]interface{}, 0, len(state.PeerCertificates))
+	for _, cert := range state.PeerCertificates {
+		certificates = append(certificates, certificateResource(cert))
+	}
+
+	return map[string]interface{}{
+		"version":           tls.VersionName(state.Version),
+		"cipher-suite":      tls.CipherSuiteName(state.CipherSuite),
+		"server-name":       state.ServerName,
+		"peer-certificates": certificates,
+	}
+}
+
+// certificateResource formats a certificate for the domain resources. Times
+// are RFC 3339 formatted.
+func certificateResource(cert *x509.Certificate) map[string]interface{} {
+	dnsNames := make([]interface{}, 0, len(cert.DNSNames))
+	for _, name := range cert.DNSNames {
+		dnsNames = append(dnsNames, name)
+	}
+
+	return map[string]interface{}{
+		"subject":              cert.Subject.String(),
+		"issuer":               cert.Issuer.String(),
+		"serial-number":        cert.SerialNumber.String(),
+		"not-before":           cert.NotBefore.UTC().Format(time.RFC3339),
+		"not-after":            cert.NotAfter.UTC().Format(time.RFC3339),
+		"dns-names":            dnsNames,
+		"is-ca":                cert.IsCA,
+		"signature-algorithm":  cert.SignatureAlgorithm.String(),
+		"public-key-algorithm": cert.PublicKeyAlgorithm.String(),
+	}
+}
+
+// timingResource formats the timing for the domain resources, in seconds
+func timingResource(timing Timing) map[string]interface{} {
+	return map[string]interface{}{
+		"dns":           timing.DNS.Seconds(),
+		"connect":       timing.Connect.Seconds(),
+		"tls-handshake": timing.TLSHandshake.Seconds(),
+		"first-byte":    timing.FirstByte.Seconds(),
+		"total":         timing.Total.Seconds(),
+	}
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestValidateMethods(t *testing.T) {
+	tests := map[string]struct {
+		method         string
+		want           string
+		wantExecutable bool
+		wantErr        bool
+	}{
+		"default":     {method: "", want: HTTPMethodGet},
+		"head":        {method: "head", want: HTTPMethodHead},
+		"options":     {method: "Options", want: HTTPMethodOptions},
+		"post":        {method: "post", want: HTTPMethodPost, wantExecutable: true},
+		"put":         {method: "put", want: HTTPMethodPut, wantExecutable: true},
+		"patch":       {method: "PATCH", want: HTTPMethodPatch, wantExecutable: true},
+		"delete":      {method: "delete", want: HTTPMethodDelete, wantExecutable: true},
+		"unsupported": {method: "trace", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &ApiSpec{Requests: []Request{{Name: "test", URL: "http://example.com", Method: tt.method}}}
+			err := validateAndMutateSpec(spec)
+			if tt.wantErr {
+				require.ErrorContains(t, err, "unsupported method")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, spec.Requests[0].Method)
+			require.Equal(t, tt.wantExecutable, spec.executable)
+		})
+	}
+}
+
+func TestGetResourcesMethods(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		switch r.Method {
+		case http.MethodHead, http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodOptions:
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, map[string]any{"method": r.Method, "body": string(body)})
+		}
+	}))
+	defer svr.Close()
+
+	for _, method := range []string{"head", "put", "patch", "delete", "options"} {
+		t.Run(method, func(t *testing.T) {
+			api, err := CreateApiDomain(&ApiSpec{Requests: []Request{{Name: "test", URL: svr.URL, Method: method, Body: `{"cat": "Li Shou"}`}}})
+			require.NoError(t, err)
+
+			drs, err := api.GetResources(context.Background())
+			require.NoError(t, err)
+			dr := drs["test"].(types.DomainResources)
+			headers := dr["headers"].(map[string]interface{})
+			require.Equal(t, api.(ApiDomain).Spec.Requests[0].Method, headers["x-method"])
+
+			switch method {
+			case "put", "patch":
+				require.Equal(t, `{"cat": "Li Shou"}`, dr["response"].(map[string]any)["body"])
+			case "options":
+				require.Equal(t, "GET, HEAD", headers["allow"])
+				require.Nil(t, dr["response"])
+			}
+		})
+	}
+}
+
+func TestGetResourcesTLSAndTiming(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=63072000")
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Origin")
+		writeJSON(w, map[string]any{"ok": true})
+	}))
+	defer svr.Close()
+
+	// trust the test server certificate
+	dir := t.TempDir()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server-ca.crt"), serverCA, 0600))
+	ctx := context.WithValue(context.Background(), types.LulaValidationWorkDir, dir)
+
+	api, err := CreateApiDomain(&ApiSpec{
+		Requests: []Request{{Name: "test", URL: svr.URL}},
+		Options:  &ApiOpts{Auth: &AuthOpts{MTLS: &MTLSAuth{CAFile: "server-ca.crt"}}},
+	})
+	require.NoError(t, err)
+
+	drs, err := api.GetResources(ctx)
+	require.NoError(t, err)
+	dr := drs["test"].(types.DomainResources)
+
+	headers := dr["headers"].(map[string]interface{})
+	require.Equal(t, "max-age=63072000", headers["strict-transport-security"])
+	require.Equal(t, "Accept, Origin", headers["vary"])
+
+	tlsState := dr["tls"].(map[string]interface{})
+	require.Contains(t, tlsState["version"], "TLS")
+	certs := tlsState["peer-certificates"].([]interface{})
+	require.Len(t, certs, 1)
+	cert := certs[0].(map[string]interface{})
+	require.Equal(t, svr.Certificate().NotAfter.UTC().Format("2006-01-02T15:04:05Z07:00"), cert["not-after"])
+	require.Equal(t, svr.Certificate().SerialNumber.String(), cert["serial-number"])
+
+	timing := dr["timing"].(map[string]interface{})
+	require.Greater(t, timing["total"], float64(0))
+	require.Greater(t, timing["tls-handshake"], float64(0))
+	require.GreaterOrEqual(t, timing["total"], timing["first-byte"])
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/defenseunicorns/lula/src/pkg/message"
+)
+
+// doHTTPReqWithRetry sends the request, retrying with exponential backoff on
+// transport errors and 429 or 5xx responses. A nil retry sends the request once,
+// as do requests with mutating methods unless the retry allows them.
+func doHTTPReqWithRetry(ctx context.Context, r httpRequest, retry *RetryOpts) (*APIResponse, error) {
+	attempts := 1
+	if retry != nil && (retry.Mutating || slices.Contains(retryableMethods, r.method)) {
+		attempts = retry.Attempts
+	}
+
+	var response *APIResponse
+	var err error
+	for attempt := 1; ; attempt++ {
+		response, err = doHTTPReq(ctx, r)
+		if response != nil {
+			response.Attempts = attempt
+		}
+		if attempt >= attempts || !shouldRetry(ctx, response, err) {
+			break
+		}
+
+		delay := retryDelay(retry, attempt, response)
+		message.Debugf("retrying %s in %s (attempt %d of %d)", r.url.Redacted(), delay, attempt+1, attempts)
+		if err := sleep(ctx, delay); err != nil {
+			return response, err
+		}
+	}
+
+	return response, err
+}
+
+// shouldRetry returns true for transport errors and 429 or 5xx responses
+func shouldRetry(ctx context.Context, response *APIResponse, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if response == nil {
+		return err != nil
+	}
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+}
+
+// retryDelay returns the delay before the next attempt. A Retry-After header
+// takes precedence over the exponential backoff; both are capped at max-backoff.
+func retryDelay(retry *RetryOpts, attempt int, response *APIResponse) time.Duration {
+	delay := retry.backoff
+	for i := 1; i < attempt && delay < retry.maxBackoff; i++ {
+		delay *= 2
+	}
+	if response != nil {
+		if after, ok := parseRetryAfter(response.Headers.Get("Retry-After")); ok {
+			delay = after
+		}
+	}
+	return min(delay, retry.maxBackoff)
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for the duration, returning early with an error if ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return errors.Join(errors.New("canceled while waiting to retry"), ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestGetResourcesWithRetry(t *testing.T) {
+	var calls int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/flaky":
+			if calls < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			writeJSON(w, map[string]any{"ok": true})
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	tests := map[string]struct {
+		path         string
+		method       string
+		retry        *RetryOpts
+		wantStatus   int
+		wantAttempts int
+	}{
+		"succeeds after retries": {
+			path:         "/flaky",
+			retry:        &RetryOpts{Attempts: 5, Backoff: "1ms"},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		"gives up after attempts": {
+			path:         "/down",
+			retry:        &RetryOpts{Attempts: 2, Backoff: "1ms"},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 2,
+		},
+		"mutating methods are not retried": {
+			path:         "/down",
+			method:       HTTPMethodPost,
+			retry:        &RetryOpts{Attempts: 3, Backoff: "1ms"},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		"mutating methods are retried when allowed": {
+			path:         "/down",
+			method:       HTTPMethodPost,
+			retry:        &RetryOpts{Attempts: 3, Backoff: "1ms", Mutating: true},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 3,
+		},
+		"client errors are not retried": {
+			path:         "/missing",
+			retry:        &RetryOpts{Backoff: "1ms"},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls = 0
+			api, err := CreateApiDomain(&ApiSpec{
+				Requests: []Request{{Name: "test", URL: svr.URL + tt.path, Method: tt.method}},
+				Options:  &ApiOpts{Retry: tt.retry},
+			})
+			require.NoError(t, err)
+
+			drs, err := api.GetResources(context.Background())
+			require.NoError(t, err)
+			dr := drs["test"].(types.DomainResources)
+			require.Equal(t, tt.wantStatus, dr["statuscode"])
+			require.Equal(t, tt.wantAttempts, dr["attempts"])
+			require.Equal(t, tt.wantAttempts, calls)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	retry := &RetryOpts{}
+	require.NoError(t, validateAndMutateRetry(retry))
+
+	require.Equal(t, time.Second, retryDelay(retry, 1, nil))
+	require.Equal(t, 4*time.Second, retryDelay(retry, 3, nil))
+	require.Equal(t, 30*time.Second, retryDelay(retry, 10, nil))
+
+	withRetryAfter := &APIResponse{Headers: http.Header{"Retry-After": []string{"7"}}}
+	require.Equal(t, 7*time.Second, retryDelay(retry, 1, withRetryAfter))
+	withRetryAfter.Headers.Set("Retry-After", "3600")
+	require.Equal(t, 30*time.Second, retryDelay(retry, 1, withRetryAfter))
+	withRetryAfter.Headers.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	require.Equal(t, time.Duration(0), retryDelay(retry, 1, withRetryAfter))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 )
@@ -11,10 +12,36 @@ import (
 var defaultTimeout = 30 * time.Second
 
 const (
-	HTTPMethodGet  string = "GET"
-	HTTPMethodPost string = "POST"
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+
+	defaultPageLimit = 100
+	defaultMaxPages  = 100
+)
+
+const (
+	PaginationLink   string = "link"
+	PaginationCursor string = "cursor"
+	PaginationOffset string = "offset"
 )
 
+const (
+	HTTPMethodGet     string = "GET"
+	HTTPMethodHead    string = "HEAD"
+	HTTPMethodPost    string = "POST"
+	HTTPMethodPut     string = "PUT"
+	HTTPMethodPatch   string = "PATCH"
+	HTTPMethodDelete  string = "DELETE"
+	HTTPMethodOptions string = "OPTIONS"
+)
+
+// mutatingMethods always mark the spec executable
+var mutatingMethods = []string{HTTPMethodPost, HTTPMethodPut, HTTPMethodPatch, HTTPMethodDelete}
+
+// retryableMethods are retried without the mutating retry option
+var retryableMethods = []string{HTTPMethodGet, HTTPMethodHead, HTTPMethodOptions}
+
 // validateAndMutateSpec validates the spec values and applies any defaults or
 // other mutations or normalizations necessary. The original values are not modified.
 // validateAndMutateSpec will validate the entire object and may return multiple
@@ -35,18 +62,42 @@ func validateAndMutateSpec(spec *ApiSpec) (errs error) {
 		errs = errors.Join(errs, err)
 	}
 
+	for k, v := range spec.Options.Headers {
+		if referencePattern.MatchString(v) {
+			errs = errors.Join(errs, fmt.Errorf("header %s: references to other requests are only supported in request options", k))
+		}
+	}
+
+	names := make(map[string]bool, len(spec.Requests))
 	for i := range spec.Requests {
 		if spec.Requests[i].Name == "" {
 			errs = errors.Join(errs, errors.New("request name cannot be empty"))
+		} else if names[spec.Requests[i].Name] {
+			errs = errors.Join(errs, fmt.Errorf("duplicate request name %s", spec.Requests[i].Name))
 		}
+		names[spec.Requests[i].Name] = true
+	}
+
+	for i := range spec.Requests {
 		if spec.Requests[i].URL == "" {
 			errs = errors.Join(errs, errors.New("request url cannot be empty"))
 		}
-		reqUrl, err := url.Parse(spec.Requests[i].URL)
-		if err != nil {
-			errs = errors.Join(errs, errors.New("invalid request url"))
-		} else {
-			spec.Requests[i].reqURL = reqUrl
+		// URLs with references are parsed once they're rendered
+		if !referencePattern.MatchString(spec.Requests[i].URL) {
+			reqUrl, err := url.Parse(spec.Requests[i].URL)
+			if err != nil {
+				errs = errors.Join(errs, errors.New("invalid request url"))
+			} else {
+				spec.Requests[i].reqURL = reqUrl
+			}
+		}
+		if f := spec.Requests[i].ForEach; f != "" {
+			match := referencePattern.FindStringSubmatch(f)
+			if match == nil || match[0] != f {
+				errs = errors.Join(errs, fmt.Errorf("request %s: for-each must be a single reference, e.g. ${list.response}", spec.Requests[i].Name))
+			} else if name, _, _ := strings.Cut(match[1], "."); !names[name] {
+				errs = errors.Join(errs, fmt.Errorf("request %s: for-each references unknown request %s", spec.Requests[i].Name, name))
+			}
 		}
 		if spec.Requests[i].Params != nil {
 			queryParameters := url.Values{}
@@ -56,28 +107,41 @@ func validateAndMutateSpec(spec *ApiSpec) (errs error) {
 			spec.Requests[i].reqParameters = queryParameters
 		}
 		if spec.Requests[i].Options != nil {
-			err = validateAndMutateOptions(spec.Requests[i].Options)
+			err := validateAndMutateOptions(spec.Requests[i].Options)
 			if err != nil {
 				errs = errors.Join(errs, err)
 			}
 		}
 
-		switch m := spec.Requests[i].Method; strings.ToLower(m) {
-		case "post":
-			spec.Requests[i].Method = HTTPMethodPost
-		case "get", "":
-			fallthrough
-		default:
+		if spec.Requests[i].Pagination != nil {
+			err := validateAndMutatePagination(spec.Requests[i].Pagination)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("request %s: %w", spec.Requests[i].Name, err))
+			}
+		}
+
+		switch m := strings.ToUpper(spec.Requests[i].Method); m {
+		case "":
 			spec.Requests[i].Method = HTTPMethodGet
+		case HTTPMethodGet, HTTPMethodHead, HTTPMethodPost, HTTPMethodPut, HTTPMethodPatch, HTTPMethodDelete, HTTPMethodOptions:
+			spec.Requests[i].Method = m
+		default:
+			errs = errors.Join(errs, fmt.Errorf("request %s: unsupported method %s", spec.Requests[i].Name, spec.Requests[i].Method))
 		}
 
 		if !spec.executable { // we only need to set this once
-			if spec.Requests[i].Executable {
+			if spec.Requests[i].Executable || slices.Contains(mutatingMethods, spec.Requests[i].Method) {
 				spec.executable = true
 			}
 		}
 	}
 
+	order, err := orderRequests(spec.Requests)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	spec.order = order
+
 	return errs
 }
 
@@ -106,5 +170,83 @@ func validateAndMutateOptions(opts *ApiOpts) (errs error) {
 		opts.proxyURL = proxyURL
 	}
 
+	if opts.Auth != nil {
+		if err := validateAuth(opts.Auth); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	if opts.Retry != nil {
+		if err := validateAndMutateRetry(opts.Retry); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func validateAndMutateRetry(retry *RetryOpts) (errs error) {
+	if retry.Attempts < 0 {
+		errs = errors.Join(errs, errors.New("retry attempts cannot be negative"))
+	}
+	if retry.Attempts == 0 {
+		retry.Attempts = defaultRetryAttempts
+	}
+
+	retry.backoff = defaultRetryBackoff
+	if retry.Backoff != "" {
+		duration, err := time.ParseDuration(retry.Backoff)
+		if err != nil || duration < 0 {
+			errs = errors.Join(errs, fmt.Errorf("invalid retry backoff string: %s", retry.Backoff))
+		}
+		retry.backoff = duration
+	}
+
+	retry.maxBackoff = defaultRetryMaxBackoff
+	if retry.MaxBackoff != "" {
+		duration, err := time.ParseDuration(retry.MaxBackoff)
+		if err != nil || duration < 0 {
+			errs = errors.Join(errs, fmt.Errorf("invalid retry max-backoff string: %s", retry.MaxBackoff))
+		}
+		retry.maxBackoff = duration
+	}
+
+	return errs
+}
+
+func validateAndMutatePagination(p *Pagination) (errs error) {
+	switch p.Type {
+	case PaginationLink:
+	case PaginationCursor:
+		if p.CursorField == "" {
+			errs = errors.Join(errs, errors.New("cursor pagination requires a cursor-field"))
+		}
+		if p.CursorParam == "" {
+			errs = errors.Join(errs, errors.New("cursor pagination requires a cursor-param"))
+		}
+	case PaginationOffset:
+		if p.OffsetParam == "" {
+			p.OffsetParam = "offset"
+		}
+		if p.LimitParam == "" {
+			p.LimitParam = "limit"
+		}
+	default:
+		errs = errors.Join(errs, fmt.Errorf("invalid pagination type %q, must be one of %s, %s or %s", p.Type, PaginationLink, PaginationCursor, PaginationOffset))
+	}
+
+	if p.Limit < 0 {
+		errs = errors.Join(errs, errors.New("pagination limit cannot be negative"))
+	}
+	if p.Limit == 0 && p.Type == PaginationOffset {
+		p.Limit = defaultPageLimit
+	}
+	if p.MaxPages < 0 {
+		errs = errors.Join(errs, errors.New("pagination max-pages cannot be negative"))
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = defaultMaxPages
+	}
+
 	return errs
 }
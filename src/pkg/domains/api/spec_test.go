@@ -145,6 +145,7 @@ func TestValidateAndMutateSpec(t *testing.T) {
 					},
 				},
 				Options: &ApiOpts{timeout: &defaultTimeout},
+				order:   []int{0},
 			},
 			0,
 		},
@@ -180,7 +181,9 @@ func TestValidateAndMutateSpec(t *testing.T) {
 						Method: "POST",
 					},
 				},
-				Options: &ApiOpts{timeout: &defaultTimeout},
+				Options:    &ApiOpts{timeout: &defaultTimeout},
+				executable: true,
+				order:      []int{0},
 			},
 			0,
 		},
@@ -45,6 +45,9 @@ type ApiSpec struct {
 	// internally-managed fields executable will be set to true during spec
 	// validation if *any* of the requests are flagged executable
 	executable bool
+	// order is the order the requests are made in, so that requests come after
+	// the requests they reference
+	order []int
 }
 
 // Request is a single API request
@@ -55,6 +58,13 @@ type Request struct {
 	Method     string            `json:"method,omitempty" yaml:"method,omitempty"`
 	Body       string            `json:"body,omitempty" yaml:"body,omitempty"`
 	Executable bool              `json:"executable,omitempty" yaml:"executable,omitempty"`
+	// ForEach is a reference to an array in the resource of another request,
+	// e.g. ${list.response.items}. The request is made once for each element,
+	// which can be referenced as ${item}.
+	ForEach string `json:"for-each,omitempty" yaml:"for-each,omitempty"`
+	// Pagination configures fetching multiple pages of results, which are merged
+	// into a single response array.
+	Pagination *Pagination `json:"pagination,omitempty" yaml:"pagination,omitempty"`
 	// ApiOpts specific to this request. If ApiOpts is present, values in the
 	// ApiSpec-level Options are ignored for this request.
 	Options *ApiOpts `json:"options,omitempty" yaml:"options,omitempty"`
@@ -69,8 +79,53 @@ type ApiOpts struct {
 	Timeout string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 	Proxy   string            `json:"proxy,omitempty" yaml:"proxy,omitempty"`
 	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Auth configures authentication for the requests
+	Auth *AuthOpts `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Retry configures retries for requests that fail with a 429 or 5xx status code
+	Retry *RetryOpts `json:"retry,omitempty" yaml:"retry,omitempty"`
 
 	// internally-managed options
 	timeout  *time.Duration
 	proxyURL *url.URL
 }
+
+// Pagination configures how multiple pages of results are fetched for a request
+type Pagination struct {
+	// Type is the pagination strategy: link, cursor or offset
+	Type string `json:"type" yaml:"type"`
+	// Items is the dot-separated path to the array of results in each page.
+	// Defaults to the whole response, which must then be an array.
+	Items string `json:"items,omitempty" yaml:"items,omitempty"`
+	// CursorField is the dot-separated path to the cursor for the next page in
+	// the response, required for cursor pagination
+	CursorField string `json:"cursor-field,omitempty" yaml:"cursor-field,omitempty"`
+	// CursorParam is the query parameter the cursor is sent in, required for cursor pagination
+	CursorParam string `json:"cursor-param,omitempty" yaml:"cursor-param,omitempty"`
+	// OffsetParam is the query parameter the offset is sent in, defaults to offset
+	OffsetParam string `json:"offset-param,omitempty" yaml:"offset-param,omitempty"`
+	// LimitParam is the query parameter the page size is sent in, defaults to limit
+	LimitParam string `json:"limit-param,omitempty" yaml:"limit-param,omitempty"`
+	// Limit is the page size for offset pagination, defaults to 100
+	Limit int `json:"limit,omitempty" yaml:"limit,omitempty"`
+	// MaxPages is the maximum number of pages to fetch, defaults to 100
+	MaxPages int `json:"max-pages,omitempty" yaml:"max-pages,omitempty"`
+}
+
+// RetryOpts configures retries with exponential backoff
+type RetryOpts struct {
+	// Attempts is the maximum number of attempts, including the first, defaults to 3
+	Attempts int `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	// Backoff is the delay before the first retry, which is doubled for each
+	// subsequent retry, defaults to 1s
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// MaxBackoff is the maximum delay between attempts, including delays
+	// requested by a Retry-After header, defaults to 30s
+	MaxBackoff string `json:"max-backoff,omitempty" yaml:"max-backoff,omitempty"`
+	// Mutating also retries post, put, patch and delete requests, which may
+	// repeat a change the server already applied
+	Mutating bool `json:"mutating,omitempty" yaml:"mutating,omitempty"`
+
+	// internally-managed options
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
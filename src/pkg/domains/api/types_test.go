@@ -110,6 +110,7 @@ func TestGetResources(t *testing.T) {
 		require.NoError(t, err)
 		drs, err := api.GetResources(context.Background())
 		require.NoError(t, err)
+		withoutVolatile(drs, apiReqName)
 
 		want := types.DomainResources{
 			apiReqName: types.DomainResources{
@@ -120,6 +121,7 @@ func TestGetResources(t *testing.T) {
 				},
 				"status":     "200 OK",
 				"statuscode": 200,
+				"tls":        map[string]interface{}(nil),
 			}}
 		require.Equal(t, want, drs)
 	})
@@ -137,14 +139,24 @@ func TestGetResources(t *testing.T) {
 		require.NoError(t, err) // the spec is correct
 		drs, err := api.GetResources(context.Background())
 		require.NoError(t, err)
+		withoutVolatile(drs, apiReqName)
 		require.Equal(t, types.DomainResources{
 			apiReqName: types.DomainResources{
 				"statuscode": 400,
 				"status":     "400 Bad Request",
 				"response":   nil,
 				"raw":        json.RawMessage(nil),
+				"tls":        map[string]interface{}(nil),
 			}},
 			drs,
 		)
 	})
 }
+
+// withoutVolatile removes the headers and timing from the named resource, as
+// they differ between runs (e.g. the Date header).
+func withoutVolatile(drs types.DomainResources, name string) {
+	dr := drs[name].(types.DomainResources)
+	delete(dr, "headers")
+	delete(dr, "timing")
+}
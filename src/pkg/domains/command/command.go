@@ -0,0 +1,144 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/defenseunicorns/lula/src/pkg/message"
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+// Domain is a domain that is defined by a list of commands to execute locally
+type Domain struct {
+	Spec *Spec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+func CreateDomain(spec *Spec) (types.Domain, error) {
+	// Check validity of spec
+	err := validateAndMutateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return Domain{spec}, nil
+}
+
+// GetResources runs each command in order and collects the output, exit code
+// and duration keyed by the command name. A non-zero exit code is not treated
+// as an error, it is recorded for the provider to evaluate.
+func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error) {
+	workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
+	if !ok {
+		// if unset, assume lula is already working in the same directory the inputFile is in
+		workDir = "."
+	}
+
+	drs := make(types.DomainResources, len(d.Spec.Commands))
+	var errs error
+	for _, cmd := range d.Spec.Commands {
+		resource, err := runCommand(ctx, cmd, workDir)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("command %s: %w", cmd.Name, err))
+		}
+		drs[cmd.Name] = resource
+	}
+
+	return drs, errs
+}
+
+// IsExecutable returns true; the command domain runs arbitrary executables.
+func (d Domain) IsExecutable() bool { return true }
+
+// runCommand executes a single command and returns its resource. The resource is
+// always populated, even if an error is returned, so it can be used for reporting.
+func runCommand(ctx context.Context, cmd Command, workDir string) (types.DomainResources, error) {
+	resource := types.DomainResources{
+		"stdout":    "",
+		"stderr":    "",
+		"exit-code": -1,
+		"duration":  float64(0),
+	}
+
+	timeout := defaultTimeout
+	if cmd.timeout != nil {
+		timeout = *cmd.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, cmd.Command, cmd.Args...) // #nosec G204
+	c.Dir = workDir
+	if cmd.WorkingDir != "" {
+		if filepath.IsAbs(cmd.WorkingDir) {
+			c.Dir = cmd.WorkingDir
+		} else {
+			c.Dir = filepath.Join(workDir, cmd.WorkingDir)
+		}
+	}
+	if len(cmd.Env) > 0 {
+		c.Env = os.Environ()
+		for k, v := range cmd.Env {
+			c.Env = append(c.Env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	message.Debugf("running command %q with args %v", cmd.Command, cmd.Args)
+
+	start := time.Now()
+	runErr := c.Run()
+	resource["duration"] = time.Since(start).Seconds()
+	resource["stdout"] = stdout.String()
+	resource["stderr"] = stderr.String()
+
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return resource, fmt.Errorf("timed out after %s", timeout)
+		} else if errors.As(runErr, &exitErr) {
+			resource["exit-code"] = exitErr.ExitCode()
+		} else {
+			return resource, runErr
+		}
+	} else {
+		resource["exit-code"] = c.ProcessState.ExitCode()
+	}
+
+	parsed, err := parseOutput(stdout.Bytes(), cmd.Parser)
+	if err != nil {
+		return resource, fmt.Errorf("error parsing stdout as %s: %w", cmd.Parser, err)
+	}
+	resource["stdout"] = parsed
+
+	return resource, nil
+}
+
+// parseOutput parses the output according to the parser, unparsed output is returned as a string
+func parseOutput(output []byte, parser string) (interface{}, error) {
+	var parsed interface{}
+	switch parser {
+	case OutputParserJSON:
+		if err := json.Unmarshal(output, &parsed); err != nil {
+			return nil, err
+		}
+	case OutputParserYAML:
+		if err := yaml.Unmarshal(output, &parsed); err != nil {
+			return nil, err
+		}
+	default:
+		parsed = string(output)
+	}
+	return parsed, nil
+}
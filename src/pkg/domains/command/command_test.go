@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+var _ types.Domain = (*Domain)(nil)
+
+func TestGetResources(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands rely on sh, echo and sleep")
+	}
+	ignoreDuration := cmpopts.IgnoreMapEntries(func(k string, _ interface{}) bool { return k == "duration" })
+
+	tests := map[string]struct {
+		commands []Command
+		want     types.DomainResources
+		wantErr  bool
+	}{
+		"stdout as string": {
+			commands: []Command{{Name: "echo", Command: "echo", Args: []string{"hello"}}},
+			want: types.DomainResources{
+				"echo": types.DomainResources{"stdout": "hello\n", "stderr": "", "exit-code": 0},
+			},
+		},
+		"stdout as json": {
+			commands: []Command{{Name: "json", Command: "echo", Args: []string{`{"cat": "Cheetarah"}`}, Parser: "json"}},
+			want: types.DomainResources{
+				"json": types.DomainResources{"stdout": map[string]interface{}{"cat": "Cheetarah"}, "stderr": "", "exit-code": 0},
+			},
+		},
+		"stdout as yaml": {
+			commands: []Command{{Name: "yaml", Command: "echo", Args: []string{"cat: Li Shou"}, Parser: "yaml"}},
+			want: types.DomainResources{
+				"yaml": types.DomainResources{"stdout": map[string]interface{}{"cat": "Li Shou"}, "stderr": "", "exit-code": 0},
+			},
+		},
+		"non-zero exit code and stderr": {
+			commands: []Command{{Name: "fail", Command: "sh", Args: []string{"-c", "echo oops >&2; exit 3"}}},
+			want: types.DomainResources{
+				"fail": types.DomainResources{"stdout": "", "stderr": "oops\n", "exit-code": 3},
+			},
+		},
+		"env and working directory": {
+			commands: []Command{{
+				Name:       "env",
+				Command:    "sh",
+				Args:       []string{"-c", `echo "$LULA_TEST" && cat data.txt`},
+				Env:        map[string]string{"LULA_TEST": "lizard"},
+				WorkingDir: "testdata",
+			}},
+			want: types.DomainResources{
+				"env": types.DomainResources{"stdout": "lizard\nSnakob\n", "stderr": "", "exit-code": 0},
+			},
+		},
+		"invalid json output": {
+			commands: []Command{{Name: "json", Command: "echo", Args: []string{"not json"}, Parser: "json"}},
+			want: types.DomainResources{
+				"json": types.DomainResources{"stdout": "not json\n", "stderr": "", "exit-code": 0},
+			},
+			wantErr: true,
+		},
+		"missing executable": {
+			commands: []Command{{Name: "missing", Command: "lula-command-that-does-not-exist"}},
+			want: types.DomainResources{
+				"missing": types.DomainResources{"stdout": "", "stderr": "", "exit-code": -1},
+			},
+			wantErr: true,
+		},
+		"timeout": {
+			commands: []Command{{Name: "sleep", Command: "sleep", Args: []string{"5"}, Timeout: "100ms"}},
+			want: types.DomainResources{
+				"sleep": types.DomainResources{"stdout": "", "stderr": "", "exit-code": -1},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := CreateDomain(&Spec{Commands: tt.commands})
+			require.NoError(t, err)
+
+			resources, err := d.GetResources(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if diff := cmp.Diff(tt.want, resources, ignoreDuration); diff != "" {
+				t.Fatalf("wrong result:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestIsExecutable(t *testing.T) {
+	d, err := CreateDomain(&Spec{Commands: []Command{{Name: "echo", Command: "echo"}}})
+	require.NoError(t, err)
+	require.True(t, d.IsExecutable())
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var defaultTimeout = 30 * time.Second
+
+const (
+	OutputParserString string = "string"
+	OutputParserJSON   string = "json"
+	OutputParserYAML   string = "yaml"
+)
+
+// Spec contains a list of commands to execute
+type Spec struct {
+	Commands []Command `json:"commands" yaml:"commands"`
+}
+
+// Command is a single executable to run, the result of which is stored under Name
+type Command struct {
+	// Name is the key the command result is stored under in the domain resources
+	Name string `json:"name" yaml:"name"`
+	// Command is the executable to run, it is not run through a shell
+	Command string `json:"command" yaml:"command"`
+	// Args are the arguments passed to the executable
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+	// Env contains additional environment variables, appended to the environment of the lula process
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// WorkingDir is the directory the command is run in, relative paths are resolved from the
+	// directory of the validation
+	WorkingDir string `json:"working-dir,omitempty" yaml:"working-dir,omitempty"`
+	// Parser is used to parse stdout, enum: string, json, yaml. Defaults to string.
+	Parser string `json:"parser,omitempty" yaml:"parser,omitempty"`
+	// Timeout is the maximum duration the command may run for, defaults to 30s
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// internally-managed options
+	timeout *time.Duration
+}
+
+// validateAndMutateSpec validates the spec values and applies any defaults or
+// other mutations or normalizations necessary.
+// validateAndMutateSpec will validate the entire object and may return multiple
+// errors.
+func validateAndMutateSpec(spec *Spec) (errs error) {
+	if spec == nil {
+		return errors.New("spec is required")
+	}
+	if len(spec.Commands) == 0 {
+		errs = errors.Join(errs, errors.New("some commands must be specified"))
+	}
+
+	names := make(map[string]bool, len(spec.Commands))
+	for i := range spec.Commands {
+		cmd := &spec.Commands[i]
+		if cmd.Name == "" {
+			errs = errors.Join(errs, errors.New("command name cannot be empty"))
+		} else if names[cmd.Name] {
+			errs = errors.Join(errs, fmt.Errorf("command name %s must be unique", cmd.Name))
+		}
+		names[cmd.Name] = true
+
+		if cmd.Command == "" {
+			errs = errors.Join(errs, errors.New("command cannot be empty"))
+		}
+
+		switch p := strings.ToLower(cmd.Parser); p {
+		case "":
+			cmd.Parser = OutputParserString
+		case OutputParserString, OutputParserJSON, OutputParserYAML:
+			cmd.Parser = p
+		default:
+			errs = errors.Join(errs, fmt.Errorf("unsupported parser: %s", cmd.Parser))
+		}
+
+		if cmd.Timeout != "" {
+			duration, err := time.ParseDuration(cmd.Timeout)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("invalid timeout string: %s", cmd.Timeout))
+			}
+			cmd.timeout = &duration
+		}
+		if cmd.timeout == nil {
+			timeout := defaultTimeout
+			cmd.timeout = &timeout
+		}
+	}
+
+	return errs
+}
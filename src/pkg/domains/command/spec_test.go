@@ -0,0 +1,75 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAndMutateSpec(t *testing.T) {
+	tenSeconds := 10 * time.Second
+
+	tests := map[string]struct {
+		input    *Spec
+		want     *Spec
+		wantErrs []string
+	}{
+		"error: nil input": {
+			input:    nil,
+			wantErrs: []string{"spec is required"},
+		},
+		"error: no commands": {
+			input:    &Spec{},
+			wantErrs: []string{"some commands must be specified"},
+		},
+		"defaults are populated": {
+			input: &Spec{Commands: []Command{{Name: "echo", Command: "echo"}}},
+			want:  &Spec{Commands: []Command{{Name: "echo", Command: "echo", Parser: OutputParserString, timeout: &defaultTimeout}}},
+		},
+		"parser is normalized and timeout is parsed": {
+			input: &Spec{Commands: []Command{{Name: "echo", Command: "echo", Parser: "JSON", Timeout: "10s"}}},
+			want:  &Spec{Commands: []Command{{Name: "echo", Command: "echo", Parser: OutputParserJSON, Timeout: "10s", timeout: &tenSeconds}}},
+		},
+		"several errors": {
+			input: &Spec{Commands: []Command{
+				{Name: "", Command: ""},
+				{Name: "dup", Command: "echo", Parser: "toml"},
+				{Name: "dup", Command: "echo", Timeout: "nonsense"},
+			}},
+			wantErrs: []string{
+				"command name cannot be empty",
+				"command cannot be empty",
+				"unsupported parser: toml",
+				"command name dup must be unique",
+				"invalid timeout string: nonsense",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAndMutateSpec(test.input)
+			if len(test.wantErrs) > 0 {
+				require.Error(t, err)
+				for _, want := range test.wantErrs {
+					require.ErrorContains(t, err, want)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, test.input)
+		})
+	}
+}
+
+func TestValidateAndMutateSpecDefaultTimeout(t *testing.T) {
+	spec := &Spec{Commands: []Command{{Name: "echo", Command: "echo"}, {Name: "cat", Command: "cat"}}}
+	require.NoError(t, validateAndMutateSpec(spec))
+
+	// each command gets its own copy of the default
+	require.NotSame(t, spec.Commands[0].timeout, spec.Commands[1].timeout)
+	*spec.Commands[0].timeout = time.Second
+	require.Equal(t, 30*time.Second, defaultTimeout)
+	require.Equal(t, defaultTimeout, *spec.Commands[1].timeout)
+}
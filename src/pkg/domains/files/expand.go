@@ -0,0 +1,294 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+// defaultMaxFiles is the maximum number of files a single entry may expand to
+// if MaxFiles is unset.
+const defaultMaxFiles = 1000
+
+// expandedKeySeparator separates the entry name from the relative file path in
+// the internal names of expanded entries. It can't appear in a file path, so
+// the internal names never collide with user-supplied names.
+const expandedKeySeparator = "\x00"
+
+var (
+	ErrTooManyFiles = errors.New("too many files matched")
+	ErrBadPattern   = errors.New("invalid pattern")
+)
+
+// expandedFile records the entry and relative path an expanded file belongs to.
+type expandedFile struct {
+	name string
+	rel  string
+}
+
+// expansion is the result of expanding directory and glob entries into
+// individual files.
+type expansion struct {
+	// filepaths contains the unexpanded entries as well as one entry per expanded file
+	filepaths []FileInfo
+	// files maps the internal name of each expanded file to its entry
+	files map[string]expandedFile
+	// sets contains the names of all entries that were expanded, matching files or not
+	sets []string
+}
+
+// expandFilepaths replaces each local directory or glob entry with one entry
+// per matched file. Entries that failed to expand are dropped and reported in
+// the returned error, but are included in the sets so they're present in the
+// resources for reporting purposes.
+func expandFilepaths(filepaths []FileInfo, workDir string) (expansion, error) {
+	exp := expansion{
+		filepaths: make([]FileInfo, 0, len(filepaths)),
+		files:     make(map[string]expandedFile),
+	}
+	var errs error
+
+	for _, fi := range filepaths {
+		root, rels, ok, err := matchFiles(fi, workDir)
+		if !ok {
+			exp.filepaths = append(exp.filepaths, fi)
+			continue
+		}
+
+		exp.sets = append(exp.sets, fi.Name)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error expanding %s: %w", fi.Name, err))
+			continue
+		}
+
+		for _, rel := range rels {
+			name := fi.Name + expandedKeySeparator + rel
+			exp.files[name] = expandedFile{name: fi.Name, rel: rel}
+			exp.filepaths = append(exp.filepaths, FileInfo{
+				Name:   name,
+				Path:   filepath.Join(root, filepath.FromSlash(rel)),
+				Parser: fi.Parser,
+			})
+		}
+	}
+
+	return exp, errs
+}
+
+// group re-keys the resources of expanded files under the name of their entry,
+// keyed by the relative file path.
+func (exp expansion) group(drs types.DomainResources) types.DomainResources {
+	if len(exp.sets) == 0 {
+		return drs
+	}
+
+	for _, name := range exp.sets {
+		drs[name] = map[string]interface{}{}
+	}
+	for name, ef := range exp.files {
+		v, ok := drs[name]
+		if !ok {
+			continue
+		}
+		delete(drs, name)
+		drs[ef.name].(map[string]interface{})[ef.rel] = v
+	}
+
+	return drs
+}
+
+// matchFiles lists the files matched by a local directory or glob entry. The
+// returned relative paths are slash-separated and relative to root. ok is
+// false if the entry refers to a single (or remote) file and should not be
+// expanded.
+func matchFiles(fi FileInfo, workDir string) (root string, rels []string, ok bool, err error) {
+	if isRemote(fi.Path) {
+		return "", nil, false, nil
+	}
+
+	// the expanded paths are absolute, so they don't depend on the working
+	// directory go-getter resolves relative paths from
+	p := fi.Path
+	if !filepath.IsAbs(p) {
+		p, err = filepath.Abs(filepath.Join(workDir, p))
+		if err != nil {
+			return "", nil, true, err
+		}
+	}
+
+	// an existing path is used literally, even if its name contains glob
+	// characters, e.g. config[prod].yaml
+	var pattern string
+	recursive := fi.Recursive
+	info, statErr := os.Stat(p)
+	switch {
+	case statErr == nil && info.IsDir():
+		root = p
+	case statErr != nil && hasMeta(fi.Path):
+		root, pattern = splitPattern(p)
+		// only walk subdirectories if the pattern can match them
+		recursive = strings.Contains(pattern, "/")
+	default:
+		return "", nil, false, nil
+	}
+
+	maxFiles := fi.MaxFiles
+	if maxFiles == 0 {
+		maxFiles = defaultMaxFiles
+	}
+
+	err = filepath.WalkDir(root, func(current string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if current == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// skip symlinks and other irregular files; following symlinks is a security concern
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if pattern != "" && !matchPath(pattern, rel) {
+			return nil
+		}
+		if !filterFile(fi.Include, fi.Exclude, rel) {
+			return nil
+		}
+
+		rels = append(rels, rel)
+		if len(rels) > maxFiles {
+			return fmt.Errorf("%w: more than %d files", ErrTooManyFiles, maxFiles)
+		}
+		return nil
+	})
+	if err != nil {
+		return root, nil, true, err
+	}
+
+	return root, rels, true, nil
+}
+
+// validatePatterns checks the include and exclude patterns of the entry are well-formed.
+func validatePatterns(fi FileInfo) (errs error) {
+	patterns := append(append([]string{}, fi.Include...), fi.Exclude...)
+	if !isRemote(fi.Path) && hasMeta(fi.Path) {
+		patterns = append(patterns, filepath.ToSlash(fi.Path))
+	}
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%w: %s", ErrBadPattern, pattern))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// filterFile returns true if rel matches any include pattern (or there are
+// none) and does not match any exclude pattern.
+func filterFile(include, exclude []string, rel string) bool {
+	included := len(include) == 0
+	for _, pattern := range include {
+		if matchFilter(pattern, rel) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, pattern := range exclude {
+		if matchFilter(pattern, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilter matches patterns without a separator against the file name, and
+// patterns with a separator against the whole relative path.
+func matchFilter(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchPath(pattern, rel)
+}
+
+// matchPath matches a slash-separated path against a pattern, where each
+// segment follows path.Match and a "**" segment matches zero or more segments.
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// splitPattern splits a glob path into the static directory prefix and the
+// slash-separated pattern relative to it.
+func splitPattern(p string) (root, pattern string) {
+	segments := strings.Split(filepath.ToSlash(p), "/")
+	i := 0
+	for i < len(segments) && !hasMeta(segments[i]) {
+		i++
+	}
+
+	root = strings.Join(segments[:i], "/")
+	if root == "" {
+		if filepath.IsAbs(p) {
+			root = "/"
+		} else {
+			root = "."
+		}
+	}
+	return filepath.FromSlash(root), strings.Join(segments[i:], "/")
+}
+
+func hasMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// isRemote returns true if the path is a URL or uses a go-getter forced getter
+// (e.g. git::), which can't be expanded.
+func isRemote(p string) bool {
+	return strings.Contains(p, "://") || strings.Contains(p, "::")
+}
@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/open-policy-agent/conftest/parser"
 
@@ -20,15 +21,29 @@ type Domain struct {
 
 // GetResources gathers the input files to be tested.
 func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error) {
-	var workDir string
-	var ok bool
-	var errs error
-	tmpDRs := make(map[string]interface{})
-	if workDir, ok = ctx.Value(types.LulaValidationWorkDir).(string); !ok {
+	workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
+	if !ok {
 		// if unset, assume lula is already working in the same directory the inputFile is in
 		workDir = "."
 	}
 
+	// expand any directory or glob entries into one entry per file, and
+	// group the resulting resources under the entry name afterwards.
+	exp, expandErr := expandFilepaths(d.Spec.Filepaths, workDir)
+	drs, err := getResources(ctx, exp.filepaths, workDir)
+	if drs != nil {
+		drs = exp.group(drs)
+	}
+
+	return drs, errors.Join(expandErr, err)
+}
+
+// getResources copies the files to a temporary location and parses them,
+// keyed by the user-supplied name.
+func getResources(ctx context.Context, filepaths []FileInfo, workDir string) (types.DomainResources, error) {
+	var errs error
+	tmpDRs := make(map[string]interface{})
+
 	dst, err := os.MkdirTemp("", "lula-files-")
 	if err != nil {
 		// allow returning on error here?
@@ -51,8 +66,9 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 	filesWithParsers := make(map[string][]FileInfo, 0)
 
 	// Copy files to a temporary location. In this loop we only grab files that
-	// don't have configured parsers.
-	for _, fi := range d.Spec.Filepaths {
+	// don't have configured parsers. Each file is copied into its own
+	// directory so files with the same base name don't overwrite each other.
+	for i, fi := range filepaths {
 		if fi.Parser != "" {
 			if fi.Parser == "string" {
 				unstructuredFiles = append(unstructuredFiles, fi)
@@ -63,7 +79,8 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 			}
 		}
 
-		realdst := filepath.Join(dst, filepath.Base(fi.Path))
+		subdir := strconv.Itoa(i)
+		realdst := filepath.Join(dst, subdir, filepath.Base(fi.Path))
 		filename, err := copyFile(ctx, realdst, fi.Path, workDir)
 		if err != nil {
 			// Assign empty data value for reporting purposes
@@ -73,7 +90,7 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 		}
 
 		// and save this info for later
-		filenames[filename] = fi.Name
+		filenames[filepath.Join(subdir, filename)] = fi.Name
 	}
 
 	// get a list of all the files we just downloaded in the temporary directory
@@ -116,16 +133,18 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 			return drs, err
 		}
 
-		for _, fi := range filesByParser {
-			dst := filepath.Join(parserDir, filepath.Base(fi.Path))
+		for i, fi := range filesByParser {
+			subdir := strconv.Itoa(i)
+			dst := filepath.Join(parserDir, subdir, filepath.Base(fi.Path))
 			relname, err := copyFile(ctx, dst, fi.Path, workDir)
 			if err != nil {
 				drs[fi.Name] = map[string]interface{}{}
 				errs = errors.Join(errs, fmt.Errorf("error writing local files: %w", err))
+				continue
 			}
 
 			// and save this info for later
-			filenames[relname] = fi.Name
+			filenames[filepath.Join(subdir, relname)] = fi.Name
 		}
 
 		// get a list of all the files we just downloaded in the temporary directory
@@ -159,7 +178,7 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 			continue
 		}
 
-		dst := filepath.Clean(filepath.Join(stringdir, f.Path))
+		dst := filepath.Join(stringdir, filepath.Base(f.Path))
 		_, err = copyFile(ctx, dst, f.Path, workDir)
 		if err != nil {
 			return nil, fmt.Errorf("error writing local files: %w", err)
@@ -184,9 +203,29 @@ func (d Domain) GetResources(ctx context.Context) (types.DomainResources, error)
 func (d Domain) IsExecutable() bool { return false }
 
 func CreateDomain(spec *Spec) (types.Domain, error) {
-	if len(spec.Filepaths) == 0 {
+	if spec == nil || len(spec.Filepaths) == 0 {
 		return nil, fmt.Errorf("file-spec must not be empty")
 	}
+
+	var errs error
+	names := make(map[string]bool, len(spec.Filepaths))
+	for _, fi := range spec.Filepaths {
+		if names[fi.Name] {
+			errs = errors.Join(errs, fmt.Errorf("file name %s must be unique", fi.Name))
+		}
+		names[fi.Name] = true
+
+		if fi.MaxFiles < 0 {
+			errs = errors.Join(errs, fmt.Errorf("max-files for %s must not be negative", fi.Name))
+		}
+		if err := validatePatterns(fi); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
 	return Domain{spec}, nil
 }
 
@@ -19,6 +19,7 @@ func TestGetResource(t *testing.T) {
 			{Name: "baz", Path: "baz", Parser: "json"},
 			{Name: "arbitraryname", Path: "nested-directory/baz.hcl2"},
 			{Name: "stringtheory", Path: "arbitrary.file", Parser: "string"},
+			{Name: "brackets", Path: "config[prod].yaml"},
 		}}}
 
 		resources, err := d.GetResources(context.WithValue(context.Background(), types.LulaValidationWorkDir, "testdata"))
@@ -31,8 +32,121 @@ func TestGetResource(t *testing.T) {
 				"resource": map[string]any{"catname": map[string]any{"blackcat": map[string]any{"name": "robin"}}},
 			},
 			"stringtheory": "hello there!",
+			"brackets":     map[string]interface{}{"env": "prod"},
 		}); diff != "" {
 			t.Fatalf("wrong result:\n%s\n", diff)
 		}
 	})
+	t.Run("directories and globs", func(t *testing.T) {
+		tests := map[string]struct {
+			fi      FileInfo
+			want    map[string]interface{}
+			wantErr bool
+		}{
+			"directory": {
+				fi: FileInfo{Name: "glob", Path: "glob", Include: []string{"*.yaml", "*.json"}},
+				want: map[string]interface{}{
+					"one.yaml": map[string]interface{}{"cat": "one"},
+					"two.json": map[string]interface{}{"cat": "two"},
+				},
+			},
+			"recursive directory with exclude": {
+				fi: FileInfo{Name: "glob", Path: "glob", Recursive: true, Include: []string{"*.yaml"}, Exclude: []string{"sub/deeper/**"}},
+				want: map[string]interface{}{
+					"one.yaml":       map[string]interface{}{"cat": "one"},
+					"sub/one.yaml":   map[string]interface{}{"cat": "sub-one"},
+					"sub/three.yaml": map[string]interface{}{"cat": "three"},
+				},
+			},
+			"recursive glob": {
+				fi: FileInfo{Name: "glob", Path: "glob/**/*.yaml", Exclude: []string{"one.yaml"}},
+				want: map[string]interface{}{
+					"sub/three.yaml":       map[string]interface{}{"cat": "three"},
+					"sub/deeper/four.yaml": map[string]interface{}{"cat": "four"},
+				},
+			},
+			"glob with parser": {
+				fi: FileInfo{Name: "glob", Path: "glob/*.txt", Parser: "string"},
+				want: map[string]interface{}{
+					"notes.txt": "hello there!",
+				},
+			},
+			"no matches": {
+				fi:   FileInfo{Name: "glob", Path: "glob/*.toml"},
+				want: map[string]interface{}{},
+			},
+			"too many files": {
+				fi:      FileInfo{Name: "glob", Path: "glob", Recursive: true, MaxFiles: 2},
+				want:    map[string]interface{}{},
+				wantErr: true,
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				d, err := CreateDomain(&Spec{Filepaths: []FileInfo{tt.fi, {Name: "bar.json", Path: "bar.json"}}})
+				require.NoError(t, err)
+
+				resources, err := d.GetResources(context.WithValue(context.Background(), types.LulaValidationWorkDir, "testdata"))
+				if tt.wantErr {
+					require.ErrorIs(t, err, ErrTooManyFiles)
+				} else {
+					require.NoError(t, err)
+				}
+				if diff := cmp.Diff(resources, types.DomainResources{
+					"glob":     tt.want,
+					"bar.json": map[string]interface{}{"cat": "Cheetarah"},
+				}); diff != "" {
+					t.Fatalf("wrong result:\n%s\n", diff)
+				}
+			})
+		}
+	})
+}
+
+func TestCreateDomain(t *testing.T) {
+	tests := map[string]struct {
+		spec    *Spec
+		wantErr bool
+	}{
+		"valid":                {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "foo.yaml"}}}, wantErr: false},
+		"nil spec":             {spec: nil, wantErr: true},
+		"empty spec":           {spec: &Spec{}, wantErr: true},
+		"duplicate names":      {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "foo.yaml"}, {Name: "foo", Path: "bar.json"}}}, wantErr: true},
+		"negative max files":   {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "dir", MaxFiles: -1}}}, wantErr: true},
+		"invalid include":      {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "dir", Include: []string{"[a-"}}}}, wantErr: true},
+		"invalid glob path":    {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "dir/[a-/*.yaml"}}}, wantErr: true},
+		"remote path with ref": {spec: &Spec{Filepaths: []FileInfo{{Name: "foo", Path: "https://example.com/foo.yaml?ref=[a-"}}}, wantErr: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CreateDomain(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.yaml", "foo.yaml", true},
+		{"*.yaml", "sub/foo.yaml", false},
+		{"**/*.yaml", "foo.yaml", true},
+		{"**/*.yaml", "sub/deeper/foo.yaml", true},
+		{"sub/**", "sub/deeper/foo.yaml", true},
+		{"sub/**", "other/foo.yaml", false},
+		{"sub/*/foo.yaml", "sub/deeper/foo.yaml", true},
+		{"sub/*/foo.yaml", "sub/foo.yaml", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
 }
@@ -8,4 +8,18 @@ type FileInfo struct {
 	Name   string `json:"name" yaml:"name"`
 	Path   string `json:"path" yaml:"path"`
 	Parser string `json:"parser,omitempty" yaml:"parser,omitempty"`
+
+	// The following fields only apply when Path is a local directory or glob
+	// pattern, in which case the entry expands into one resource per matched
+	// file, keyed by the file path relative to the directory (or the static
+	// prefix of the glob) under Name.
+
+	// Recursive includes files in all subdirectories of a directory Path.
+	Recursive bool `json:"recursive,omitempty" yaml:"recursive,omitempty"`
+	// Include limits the matched files to those matching any of the patterns.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	// Exclude removes matched files matching any of the patterns.
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// MaxFiles is the maximum number of files the entry may expand to, defaults to 1000.
+	MaxFiles int `json:"max-files,omitempty" yaml:"max-files,omitempty"`
 }
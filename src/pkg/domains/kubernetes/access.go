@@ -0,0 +1,162 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccessReviewRule asks the API server whether each subject can perform each
+// verb on each resource, in each namespace
+type AccessReviewRule struct {
+	Subjects  []AccessSubject  `json:"subjects" yaml:"subjects"`
+	Verbs     []string         `json:"verbs" yaml:"verbs"`
+	Resources []AccessResource `json:"resources" yaml:"resources"`
+	// Namespaces to review the access in, empty for cluster-wide access
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+// AccessSubject is a user and/or groups, or a service account
+type AccessSubject struct {
+	User   string   `json:"user,omitempty" yaml:"user,omitempty"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// ServiceAccount is the service account as namespace/name
+	ServiceAccount string `json:"service-account,omitempty" yaml:"service-account,omitempty"`
+}
+
+// AccessResource is a resource, or one of its subresources or a named resource
+type AccessResource struct {
+	Group       string `json:"group" yaml:"group"`
+	Resource    string `json:"resource" yaml:"resource"`
+	Subresource string `json:"subresource,omitempty" yaml:"subresource,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+func (a AccessReviewRule) validate() error {
+	if len(a.Subjects) == 0 {
+		return errors.New("access-review subjects cannot be empty")
+	}
+	if len(a.Verbs) == 0 {
+		return errors.New("access-review verbs cannot be empty")
+	}
+	if len(a.Resources) == 0 {
+		return errors.New("access-review resources cannot be empty")
+	}
+	for _, subject := range a.Subjects {
+		if err := subject.validate(); err != nil {
+			return err
+		}
+	}
+	for _, verb := range a.Verbs {
+		if verb == "" {
+			return errors.New("access-review verb cannot be empty")
+		}
+	}
+	for _, resource := range a.Resources {
+		if resource.Resource == "" {
+			return errors.New("access-review resource cannot be empty")
+		}
+	}
+	return nil
+}
+
+func (s AccessSubject) validate() error {
+	if s.ServiceAccount != "" {
+		if s.User != "" || len(s.Groups) > 0 {
+			return errors.New("access-review service-account cannot be specified with user or groups")
+		}
+		if _, _, err := parseServiceAccount(s.ServiceAccount); err != nil {
+			return err
+		}
+		return nil
+	}
+	if s.User == "" && len(s.Groups) == 0 {
+		return errors.New("access-review subject must specify a user, groups or service-account")
+	}
+	return nil
+}
+
+// parseServiceAccount splits a namespace/name service account
+func parseServiceAccount(serviceAccount string) (namespace, name string, err error) {
+	namespace, name, ok := strings.Cut(serviceAccount, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid access-review service-account %q, expected namespace/name", serviceAccount)
+	}
+	return namespace, name, nil
+}
+
+// userInfo returns the user and groups the API server authenticates the subject as
+func (s AccessSubject) userInfo() (user string, groups []string, description string) {
+	if s.ServiceAccount == "" {
+		description = s.User
+		if description == "" {
+			description = "group:" + strings.Join(s.Groups, ",")
+		}
+		return s.User, s.Groups, description
+	}
+
+	namespace, name, _ := parseServiceAccount(s.ServiceAccount)
+	user = fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+	groups = []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"}
+	return user, groups, user
+}
+
+// ReviewAccess issues a SubjectAccessReview for every combination of subject,
+// verb, resource and namespace, returning the decision of each
+func ReviewAccess(ctx context.Context, cluster *Cluster, rule *AccessReviewRule) ([]map[string]interface{}, error) {
+	namespaces := rule.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	decisions := make([]map[string]interface{}, 0, len(rule.Subjects)*len(rule.Verbs)*len(rule.Resources)*len(namespaces))
+	var errs error
+	for _, subject := range rule.Subjects {
+		user, groups, description := subject.userInfo()
+		for _, resource := range rule.Resources {
+			for _, verb := range rule.Verbs {
+				for _, namespace := range namespaces {
+					review := &authorizationv1.SubjectAccessReview{
+						Spec: authorizationv1.SubjectAccessReviewSpec{
+							User:   user,
+							Groups: groups,
+							ResourceAttributes: &authorizationv1.ResourceAttributes{
+								Namespace:   namespace,
+								Verb:        verb,
+								Group:       resource.Group,
+								Resource:    resource.Resource,
+								Subresource: resource.Subresource,
+								Name:        resource.Name,
+							},
+						},
+					}
+					result, err := cluster.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+					if err != nil {
+						errs = errors.Join(errs, fmt.Errorf("error reviewing access of %s to %s %s: %w", description, verb, resource.Resource, err))
+						continue
+					}
+
+					decisions = append(decisions, map[string]interface{}{
+						"subject":     description,
+						"verb":        verb,
+						"group":       resource.Group,
+						"resource":    resource.Resource,
+						"subresource": resource.Subresource,
+						"name":        resource.Name,
+						"namespace":   namespace,
+						"allowed":     result.Status.Allowed,
+						"denied":      result.Status.Denied,
+						"reason":      result.Status.Reason,
+						"error":       result.Status.EvaluationError,
+					})
+				}
+			}
+		}
+	}
+
+	return decisions, errs
+}
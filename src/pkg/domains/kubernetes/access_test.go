@@ -0,0 +1,180 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newAccessClusterForTest returns a cluster that allows admins everything, the
+// system:serviceaccounts:app group to get pods in the app namespace and denies
+// the rest
+func newAccessClusterForTest() *Cluster {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		if attributes.Resource == "secrets" && attributes.Namespace == "broken" {
+			return true, nil, errors.New("webhook unavailable")
+		}
+
+		switch {
+		case slices.Contains(review.Spec.Groups, "system:masters"):
+			review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: true, Reason: "admin"}
+		case slices.Contains(review.Spec.Groups, "system:serviceaccounts:app") && attributes.Namespace == "app" &&
+			attributes.Resource == "pods" && attributes.Verb == "get":
+			review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: true, Reason: "app-reader"}
+		default:
+			review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: false}
+		}
+		return true, review, nil
+	})
+	return &Cluster{clientset: clientset}
+}
+
+func TestReviewAccess(t *testing.T) {
+	t.Run("decision matrix", func(t *testing.T) {
+		decisions, err := ReviewAccess(context.Background(), newAccessClusterForTest(), &AccessReviewRule{
+			Subjects: []AccessSubject{
+				{User: "alice", Groups: []string{"system:masters"}},
+				{ServiceAccount: "app/web"},
+			},
+			Verbs:      []string{"get", "delete"},
+			Resources:  []AccessResource{{Resource: "pods"}},
+			Namespaces: []string{"app", "kube-system"},
+		})
+		require.NoError(t, err)
+		require.Len(t, decisions, 8)
+
+		allowed := make([]bool, 0, len(decisions))
+		for _, decision := range decisions {
+			allowed = append(allowed, decision["allowed"].(bool))
+		}
+		require.Equal(t, []bool{true, true, true, true, true, false, false, false}, allowed)
+		require.Equal(t, map[string]interface{}{
+			"subject":     "system:serviceaccount:app:web",
+			"verb":        "get",
+			"group":       "",
+			"resource":    "pods",
+			"subresource": "",
+			"name":        "",
+			"namespace":   "app",
+			"allowed":     true,
+			"denied":      false,
+			"reason":      "app-reader",
+			"error":       "",
+		}, decisions[4])
+	})
+
+	t.Run("cluster-wide", func(t *testing.T) {
+		decisions, err := ReviewAccess(context.Background(), newAccessClusterForTest(), &AccessReviewRule{
+			Subjects:  []AccessSubject{{Groups: []string{"system:masters"}}},
+			Verbs:     []string{"create"},
+			Resources: []AccessResource{{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, decisions, 1)
+		require.Equal(t, "", decisions[0]["namespace"])
+		require.Equal(t, "group:system:masters", decisions[0]["subject"])
+		require.Equal(t, true, decisions[0]["allowed"])
+	})
+
+	t.Run("failed review", func(t *testing.T) {
+		decisions, err := ReviewAccess(context.Background(), newAccessClusterForTest(), &AccessReviewRule{
+			Subjects:   []AccessSubject{{User: "bob"}},
+			Verbs:      []string{"get"},
+			Resources:  []AccessResource{{Resource: "secrets"}},
+			Namespaces: []string{"app", "broken"},
+		})
+		require.Error(t, err)
+		require.Len(t, decisions, 1)
+		require.Equal(t, "app", decisions[0]["namespace"])
+	})
+}
+
+func TestAccessReviewRuleValidate(t *testing.T) {
+	valid := func() AccessReviewRule {
+		return AccessReviewRule{
+			Subjects:  []AccessSubject{{ServiceAccount: "app/web"}},
+			Verbs:     []string{"get"},
+			Resources: []AccessResource{{Resource: "secrets"}},
+		}
+	}
+
+	tests := map[string]struct {
+		modify  func(*AccessReviewRule)
+		wantErr bool
+	}{
+		"valid":                          {modify: func(*AccessReviewRule) {}},
+		"no subjects":                    {modify: func(r *AccessReviewRule) { r.Subjects = nil }, wantErr: true},
+		"no verbs":                       {modify: func(r *AccessReviewRule) { r.Verbs = nil }, wantErr: true},
+		"no resources":                   {modify: func(r *AccessReviewRule) { r.Resources = nil }, wantErr: true},
+		"empty subject":                  {modify: func(r *AccessReviewRule) { r.Subjects = []AccessSubject{{}} }, wantErr: true},
+		"empty verb":                     {modify: func(r *AccessReviewRule) { r.Verbs = []string{""} }, wantErr: true},
+		"empty resource":                 {modify: func(r *AccessReviewRule) { r.Resources = []AccessResource{{Group: "apps"}} }, wantErr: true},
+		"service account without name":   {modify: func(r *AccessReviewRule) { r.Subjects[0].ServiceAccount = "app" }, wantErr: true},
+		"service account with user":      {modify: func(r *AccessReviewRule) { r.Subjects[0].User = "alice" }, wantErr: true},
+		"groups only":                    {modify: func(r *AccessReviewRule) { r.Subjects = []AccessSubject{{Groups: []string{"devs"}}} }},
+		"service account with extra '/'": {modify: func(r *AccessReviewRule) { r.Subjects[0].ServiceAccount = "app/web/x" }, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := valid()
+			tt.modify(&rule)
+			err := rule.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateKubernetesDomainAccessReview(t *testing.T) {
+	rule := &AccessReviewRule{
+		Subjects:  []AccessSubject{{ServiceAccount: "app/web"}},
+		Verbs:     []string{"list"},
+		Resources: []AccessResource{{Resource: "secrets"}},
+	}
+
+	tests := map[string]struct {
+		spec    KubernetesSpec
+		wantErr bool
+	}{
+		"access review": {
+			spec: KubernetesSpec{Resources: []Resource{{Name: "access", AccessReview: rule}}},
+		},
+		"access review and logs": {
+			spec:    KubernetesSpec{Resources: []Resource{{Name: "access", AccessReview: rule, Logs: &LogsRule{Name: "web", Namespace: "app"}}}},
+			wantErr: true,
+		},
+		"invalid access review": {
+			spec:    KubernetesSpec{Resources: []Resource{{Name: "access", AccessReview: &AccessReviewRule{Verbs: []string{"list"}}}}},
+			wantErr: true,
+		},
+		"access review with manifests": {
+			spec:    KubernetesSpec{Manifests: []string{"manifests"}, Resources: []Resource{{Name: "access", AccessReview: rule}}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := CreateKubernetesDomain(&tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// reviews don't change the cluster
+			require.False(t, d.IsExecutable())
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	"github.com/defenseunicorns/lula/src/pkg/message"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/e2e-framework/klient"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+)
+
+const (
+	// LabelManagedBy marks the resources created by Lula
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// LabelRunID is the ID of the Lula run that created a resource
+	LabelRunID = "lula.dev/run-id"
+
+	managedByLula = "lula"
+)
+
+var (
+	// runID identifies the resources created by this process
+	runID = uuid.NewUUID()
+
+	// created tracks the resources created by this process that have not been
+	// destroyed, so they can be cleaned up if the process is interrupted
+	createdMu sync.Mutex
+	created   []createdObject
+)
+
+type createdObject struct {
+	client klient.Client
+	obj    *unstructured.Unstructured
+}
+
+// RunID returns the ID labeling the resources created by this process
+func RunID() string {
+	return runID
+}
+
+// labelForRun labels the object as created by this run
+func labelForRun(obj metav1.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[LabelManagedBy] = managedByLula
+	labels[LabelRunID] = runID
+	obj.SetLabels(labels)
+}
+
+func trackCreated(client klient.Client, obj *unstructured.Unstructured) {
+	createdMu.Lock()
+	defer createdMu.Unlock()
+	created = append(created, createdObject{client: client, obj: obj.DeepCopy()})
+}
+
+func untrackCreated(obj *unstructured.Unstructured) {
+	createdMu.Lock()
+	defer createdMu.Unlock()
+	for i, c := range created {
+		if sameObject(c.obj, obj) {
+			created = append(created[:i], created[i+1:]...)
+			return
+		}
+	}
+}
+
+func sameObject(a, b *unstructured.Unstructured) bool {
+	return a.GroupVersionKind().GroupKind() == b.GroupVersionKind().GroupKind() &&
+		a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
+
+// CleanupCreated deletes the resources created by this process that have not
+// been destroyed yet, newest first, without waiting for them to be removed.
+// It's used when the process is interrupted, so the usual cleanup doesn't run.
+func CleanupCreated(ctx context.Context) error {
+	createdMu.Lock()
+	remaining := created
+	created = nil
+	createdMu.Unlock()
+
+	var errs error
+	propagation := string(metav1.DeletePropagationBackground)
+	for i := len(remaining) - 1; i >= 0; i-- {
+		c := remaining[i]
+		err := c.client.Resources().Delete(ctx, c.obj, resources.WithDeletePropagation(propagation))
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = errors.Join(errs, fmt.Errorf("error deleting %s %s: %w", c.obj.GetKind(), objectKey(c.obj), err))
+			continue
+		}
+		message.Debugf("Deleted %s %s", c.obj.GetKind(), objectKey(c.obj))
+	}
+	return errs
+}
+
+// CleanupItem is a resource found by CleanupOrphans
+type CleanupItem struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	RunID      string
+	Deleted    bool
+	Error      error
+
+	gvr schema.GroupVersionResource
+}
+
+// CleanupOrphans finds the resources created by Lula, e.g. by a run that was
+// killed, and deletes them unless dryRun is set. If id is set, only the
+// resources created by that run are found.
+func CleanupOrphans(ctx context.Context, cluster *Cluster, id string, dryRun bool) ([]CleanupItem, error) {
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster is nil")
+	}
+	selector := LabelManagedBy + "=" + managedByLula + "," + LabelRunID
+	if id != "" {
+		selector += "=" + id
+	}
+
+	items, err := findOrphans(ctx, cluster, selector)
+	if dryRun {
+		return items, err
+	}
+
+	// namespaces are deleted last, as deleting them deletes their contents
+	var namespaces []*CleanupItem
+	for i := range items {
+		if items[i].gvr.Group == "" && items[i].gvr.Resource == "namespaces" {
+			namespaces = append(namespaces, &items[i])
+			continue
+		}
+		deleteOrphan(ctx, cluster, &items[i])
+	}
+	for _, item := range namespaces {
+		deleteOrphan(ctx, cluster, item)
+	}
+
+	return items, err
+}
+
+// findOrphans lists every resource type that can be listed and deleted for
+// resources matching the selector
+func findOrphans(ctx context.Context, cluster *Cluster, selector string) ([]CleanupItem, error) {
+	var errs error
+	resourceLists, err := discovery.ServerPreferredResources(cluster.clientset.Discovery())
+	if err != nil {
+		// resources from the groups that were discovered are still returned
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		errs = errors.Join(errs, err)
+	}
+
+	items := make([]CleanupItem, 0)
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") || !hasVerbs(resource.Verbs, "list", "delete") {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			found, err := cluster.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("error listing %s: %w", gvr.String(), err))
+				continue
+			}
+			for _, obj := range found.Items {
+				items = append(items, CleanupItem{
+					APIVersion: obj.GetAPIVersion(),
+					Kind:       obj.GetKind(),
+					Namespace:  obj.GetNamespace(),
+					Name:       obj.GetName(),
+					RunID:      obj.GetLabels()[LabelRunID],
+					gvr:        gvr,
+				})
+			}
+		}
+	}
+
+	return items, errs
+}
+
+func deleteOrphan(ctx context.Context, cluster *Cluster, item *CleanupItem) {
+	propagation := metav1.DeletePropagationBackground
+	err := cluster.dynamicClient.Resource(item.gvr).Namespace(item.Namespace).
+		Delete(ctx, item.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		item.Error = err
+		return
+	}
+	item.Deleted = true
+}
+
+func hasVerbs(verbs metav1.Verbs, want ...string) bool {
+	for _, w := range want {
+		if !slices.Contains(verbs, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCleanupOrphans(t *testing.T) {
+	newCluster := func() *Cluster {
+		clientset := fake.NewSimpleClientset()
+		clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "namespaces", Kind: "Namespace", Verbs: metav1.Verbs{"list", "delete"}},
+					{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"list", "delete"}},
+					{Name: "pods/log", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"get"}},
+					{Name: "bindings", Namespaced: true, Kind: "Binding", Verbs: metav1.Verbs{"create"}},
+				},
+			},
+		}
+		return &Cluster{
+			clientset: clientset,
+			dynamicClient: newFakeDynamicClient(
+				newObject("v1", "Namespace", "", "lula-test", map[string]string{LabelManagedBy: "lula", LabelRunID: "run-1"}),
+				newObject("v1", "Pod", "lula-test", "privileged", map[string]string{LabelManagedBy: "lula", LabelRunID: "run-1"}),
+				newObject("v1", "Pod", "app", "privileged", map[string]string{LabelManagedBy: "lula", LabelRunID: "run-2"}),
+				newObject("v1", "Pod", "app", "web", map[string]string{"app": "web"}),
+				// the run ID alone doesn't mark a resource as created by Lula
+				newObject("v1", "Pod", "app", "other", map[string]string{LabelRunID: "run-1"}),
+			),
+		}
+	}
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	t.Run("dry-run", func(t *testing.T) {
+		cluster := newCluster()
+		items, err := CleanupOrphans(context.Background(), cluster, "", true)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		for _, item := range items {
+			require.False(t, item.Deleted)
+		}
+
+		pods, err := cluster.dynamicClient.Resource(podsGVR).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 4)
+	})
+
+	t.Run("all runs", func(t *testing.T) {
+		cluster := newCluster()
+		items, err := CleanupOrphans(context.Background(), cluster, "", false)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		for _, item := range items {
+			require.True(t, item.Deleted)
+			require.NoError(t, item.Error)
+		}
+
+		pods, err := cluster.dynamicClient.Resource(podsGVR).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 2)
+	})
+
+	t.Run("run ID", func(t *testing.T) {
+		cluster := newCluster()
+		items, err := CleanupOrphans(context.Background(), cluster, "run-2", false)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, CleanupItem{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  "app",
+			Name:       "privileged",
+			RunID:      "run-2",
+			Deleted:    true,
+			gvr:        podsGVR,
+		}, items[0])
+	})
+}
+
+func TestLabelForRun(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}}}
+	labelForRun(ns)
+	require.Equal(t, map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		LabelManagedBy:                       "lula",
+		LabelRunID:                           RunID(),
+	}, ns.Labels)
+}
+
+func TestTrackCreated(t *testing.T) {
+	pod := newObject("v1", "Pod", "app", "web", nil)
+	trackCreated(nil, pod)
+	trackCreated(nil, newObject("v1", "Pod", "app", "db", nil))
+	t.Cleanup(func() { created = nil })
+
+	untrackCreated(newObject("v1", "Pod", "app", "web", nil))
+	require.Len(t, created, 1)
+	require.Equal(t, "db", created[0].obj.GetName())
+}
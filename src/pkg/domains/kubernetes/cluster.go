@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/e2e-framework/klient"
 )
@@ -18,13 +20,18 @@ var (
 	clusterConnectOnce  sync.Once
 	globalCluster       *Cluster
 	globalConnectionErr error
+
+	// contextClusters caches the connections to named kubeconfig contexts
+	contextClustersMu sync.Mutex
+	contextClusters   = make(map[string]*Cluster)
 )
 
 type Cluster struct {
 	clientset     kubernetes.Interface
 	kclient       klient.Client
 	watcher       watcher.StatusWatcher
-	dynamicClient *dynamic.DynamicClient
+	dynamicClient dynamic.Interface
+	config        *rest.Config
 }
 
 func GetCluster() (*Cluster, error) {
@@ -35,6 +42,27 @@ func GetCluster() (*Cluster, error) {
 	return globalCluster, globalConnectionErr
 }
 
+// GetClusterForContext returns the cluster for the named kubeconfig context,
+// or the cluster for the current context if name is empty. Connections are
+// reused across calls.
+func GetClusterForContext(name string) (*Cluster, error) {
+	if name == "" {
+		return GetCluster()
+	}
+
+	contextClustersMu.Lock()
+	defer contextClustersMu.Unlock()
+	if cluster, ok := contextClusters[name]; ok {
+		return cluster, nil
+	}
+	cluster, err := NewForContext(name)
+	if err != nil {
+		return nil, err
+	}
+	contextClusters[name] = cluster
+	return cluster, nil
+}
+
 func New() (*Cluster, error) {
 	clusterErr := errors.New("unable to connect to the cluster")
 	clientset, config, err := pkgkubernetes.ClientAndConfig()
@@ -42,6 +70,27 @@ func New() (*Cluster, error) {
 		return nil, errors.Join(clusterErr, err)
 	}
 
+	return newForConfig(clusterErr, clientset, config)
+}
+
+// NewForContext connects to the cluster of the named kubeconfig context
+func NewForContext(name string) (*Cluster, error) {
+	clusterErr := fmt.Errorf("unable to connect to the cluster for context %s", name)
+	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: name}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides).ClientConfig()
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
+	}
+
+	return newForConfig(clusterErr, clientset, config)
+}
+
+func newForConfig(clusterErr error, clientset kubernetes.Interface, config *rest.Config) (*Cluster, error) {
 	watcher, err := pkgkubernetes.WatcherForConfig(config)
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
@@ -65,6 +114,7 @@ func New() (*Cluster, error) {
 		kclient:       kclient,
 		watcher:       watcher,
 		dynamicClient: dynamicClient,
+		config:        config,
 	}, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/defenseunicorns/lula/src/pkg/common/network"
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -22,7 +23,16 @@ import (
 	"sigs.k8s.io/e2e-framework/klient/wait/conditions"
 )
 
+var (
+	// createWaitTimeout is how long a created resource is waited for to exist
+	createWaitTimeout = time.Second * 30
+	// createSettleTime is the pause after a created resource exists before it's retrieved
+	createSettleTime = time.Second * 2
+)
+
 // CreateAllResources() creates all resources and returns their status
+// Resources with dry-run are only submitted with server-side dry-run, so they
+// are not returned for cleanup by DestroyAllResources, see createdOnly
 func CreateAllResources(ctx context.Context, cluster *Cluster, resources []CreateResource) (map[string]interface{}, []string, error) {
 	collections := make(map[string]interface{}, len(resources))
 	namespaces := make([]string, 0)
@@ -36,6 +46,20 @@ func CreateAllResources(ctx context.Context, cluster *Cluster, resources []Creat
 	for _, resource := range resources {
 		var collection []map[string]interface{}
 		var err error
+		if resource.DryRun {
+			if resource.Manifest != "" {
+				collection, err = DryRunFromManifest(ctx, cluster.kclient, []byte(resource.Manifest))
+			} else {
+				collection, err = DryRunFromFile(ctx, cluster.kclient, resource.File)
+			}
+			if err != nil {
+				message.Debugf("error submitting resource with dry-run: %v", err)
+				errList = append(errList, err.Error())
+			}
+			collections[resource.Name] = collection
+			continue
+		}
+
 		// Create namespace if specified
 		if resource.Namespace != "" {
 			new, err := createNamespace(ctx, cluster.kclient, resource.Namespace)
@@ -69,14 +93,26 @@ func CreateAllResources(ctx context.Context, cluster *Cluster, resources []Creat
 		collections[resource.Name] = collection
 	}
 
-	// Check if there were any errors
+	// Check if there were any errors, returning what was created so it can be destroyed
 	if len(errList) > 0 {
-		return nil, nil, errors.New("errors creating resources encountered: " + strings.Join(errList, "; "))
+		return collections, namespaces, errors.New("errors creating resources encountered: " + strings.Join(errList, "; "))
 	}
 
 	return collections, namespaces, nil
 }
 
+// createdOnly returns the collections of resources that were created, omitting
+// the outcomes of dry-run resources which don't need to be destroyed
+func createdOnly(collections map[string]interface{}, resources []CreateResource) map[string]interface{} {
+	created := make(map[string]interface{}, len(collections))
+	for _, resource := range resources {
+		if collection, ok := collections[resource.Name]; ok && !resource.DryRun {
+			created[resource.Name] = collection
+		}
+	}
+	return created
+}
+
 // CreateResourceFromManifest() creates the resource from the manifest string
 func CreateFromManifest(ctx context.Context, client klient.Client, resourceBytes []byte) ([]map[string]interface{}, error) {
 	resources := make([]map[string]interface{}, 0)
@@ -87,9 +123,18 @@ func CreateFromManifest(ctx context.Context, client klient.Client, resourceBytes
 	}
 	for _, obj := range objArray {
 		resource, err := createResource(ctx, client, &obj)
-		if err == nil {
+		if err != nil {
+			// Not returning error, resources blocked from being created are left out
+			message.Debugf("error creating %s %s: %v", obj.GetKind(), objectKey(&obj), err)
+		}
+		// A resource that was created but not retrieved is still collected so it's destroyed
+		if resource != nil {
 			resources = append(resources, resource.Object)
 		}
+		if ctx.Err() != nil {
+			cleanResources(&resources)
+			return resources, ctx.Err()
+		}
 	}
 
 	cleanResources(&resources)
@@ -108,6 +153,8 @@ func CreateFromFile(ctx context.Context, client klient.Client, resourceFile stri
 }
 
 // DestroyAllResources() removes all the created resources
+// The resources are deleted even if ctx is done, but waiting for them to be
+// removed from the cluster stops when it is
 func DestroyAllResources(ctx context.Context, client klient.Client, collections map[string]interface{}, namespaces []string) error {
 	var errList []string // Collect errors to return at end so all resources are attempted to be destroyed
 	for _, resources := range collections {
@@ -151,13 +198,17 @@ func DestroyAllResources(ctx context.Context, client klient.Client, collections
 }
 
 // createResource() creates a resource in a k8s cluster
+// The created resource is returned with the error if it couldn't be retrieved
+// after creation, so it can still be destroyed
 func createResource(ctx context.Context, client klient.Client, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	// Create the object -> error returned when object is unable to be created
+	labelForRun(obj)
 	if err := client.Resources().Create(ctx, obj); err != nil {
 		return nil, err
 	}
+	trackCreated(client, obj)
 
-	// Wait for object to exist -> Times out at 30 seconds
+	// Wait for object to exist
 	conditionFunc := func(obj k8s.Object) bool {
 		if err := client.Resources().Get(ctx, obj.GetName(), obj.GetNamespace(), obj); err != nil {
 			return false
@@ -166,17 +217,23 @@ func createResource(ctx context.Context, client klient.Client, obj *unstructured
 	}
 	if err := wait.For(
 		conditions.New(client.Resources()).ResourceMatch(obj, conditionFunc),
-		wait.WithTimeout(time.Second*30),
+		wait.WithTimeout(createWaitTimeout),
+		wait.WithContext(ctx),
 	); err != nil {
-		return nil, nil // Not returning error, just assuming that the object was blocked or not created
+		return obj, fmt.Errorf("%s %s not found after creation: %w", obj.GetKind(), objectKey(obj), err)
 	}
 
 	// Add pause for resources to do thier thang -> this should be subsumed by the addition of wait and resources
-	time.Sleep(time.Second * 2) // Not sure if this is enough time, need to test with more complex resources
+	// Not sure if this is enough time, need to test with more complex resources
+	select {
+	case <-time.After(createSettleTime):
+	case <-ctx.Done():
+		return obj, ctx.Err()
+	}
 
 	// Get the object to return
 	if err := client.Resources().Get(ctx, obj.GetName(), obj.GetNamespace(), obj); err != nil {
-		return nil, err // Object was unable to be retrieved
+		return obj, err // Object was unable to be retrieved
 	}
 
 	return obj, nil
@@ -185,15 +242,28 @@ func createResource(ctx context.Context, client klient.Client, obj *unstructured
 // destroyResource() removes a resource from a k8s cluster
 func destroyResource(ctx context.Context, client klient.Client, obj *unstructured.Unstructured) error {
 	propagationPolicy := metav1.DeletePropagationForeground
-	if err := client.Resources().Delete(ctx, obj, resources.WithDeletePropagation(string(propagationPolicy))); err != nil {
+	err := client.Resources().Delete(context.WithoutCancel(ctx), obj, resources.WithDeletePropagation(string(propagationPolicy)))
+	if apierrors.IsNotFound(err) {
+		// Already removed, or never appeared after it was created
+		untrackCreated(obj)
+		return nil
+	}
+	if err != nil {
 		return err
 	}
+	untrackCreated(obj)
 
-	// Wait for object to be removed from the cluster -> Times out at 5 minutes
+	// Wait for object to be removed from the cluster -> Times out at 5 minutes, or when ctx is done
 	if err := wait.For(
 		conditions.New(client.Resources()).ResourceDeleted(obj),
 		wait.WithTimeout(time.Minute*5),
+		wait.WithContext(ctx),
 	); err != nil {
+		if ctx.Err() != nil {
+			// The deletion was requested, the cluster removes the object without waiting for it
+			message.Debugf("Not waiting for %s %s to be removed: %v", obj.GetKind(), objectKey(obj), ctx.Err())
+			return nil
+		}
 		return err // Object is unable to be deleted... retry logic? Or just return error?
 	}
 
@@ -211,9 +281,15 @@ func createNamespace(ctx context.Context, client klient.Client, namespace string
 		return false, nil // Namespace already exists
 	}
 
+	labelForRun(ns)
 	if err := client.Resources().Create(ctx, ns); err != nil {
 		return false, err // Namespace was unable to be created
 	}
+	trackCreated(client, &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": namespace},
+	}})
 
 	return true, nil // Namespace created successfully
 }
@@ -0,0 +1,111 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/e2e-framework/klient"
+)
+
+// newUnreadableClusterForTest returns a client for an API server that accepts
+// configmaps but never finds them, e.g. as if they were removed right after creation
+func newUnreadableClusterForTest(t *testing.T) klient.Client {
+	writeJSON := func(w http.ResponseWriter, code int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api":
+			writeJSON(w, http.StatusOK, metav1.APIVersions{Versions: []string{"v1"}})
+		case r.URL.Path == "/apis":
+			writeJSON(w, http.StatusOK, metav1.APIGroupList{})
+		case r.URL.Path == "/api/v1":
+			writeJSON(w, http.StatusOK, metav1.APIResourceList{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: metav1.Verbs{"create", "get", "delete"}},
+				},
+			})
+		case r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, err = w.Write(body)
+			require.NoError(t, err)
+		default:
+			status := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "config").Status()
+			writeJSON(w, http.StatusNotFound, &status)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := klient.New(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	return client
+}
+
+func TestCreateFromManifestNotFound(t *testing.T) {
+	timeout := createWaitTimeout
+	createWaitTimeout = time.Millisecond * 100
+	t.Cleanup(func() {
+		createWaitTimeout = timeout
+		created = nil
+	})
+
+	client := newUnreadableClusterForTest(t)
+	collection, err := CreateFromManifest(context.Background(), client, []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: app
+`))
+	require.NoError(t, err)
+	// The resource was created, so it's collected to be destroyed
+	require.Len(t, collection, 1)
+	require.Equal(t, "config", collection[0]["metadata"].(map[string]interface{})["name"])
+	require.Len(t, created, 1)
+
+	err = DestroyAllResources(context.Background(), client, map[string]interface{}{"config": collection}, nil)
+	require.NoError(t, err)
+	require.Empty(t, created)
+}
+
+func TestCreateFromManifestTimeout(t *testing.T) {
+	t.Cleanup(func() { created = nil })
+
+	client := newUnreadableClusterForTest(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	start := time.Now()
+	collection, err := CreateFromManifest(ctx, client, []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: app
+`))
+	// The wait for the resource to exist stops at the deadline rather than createWaitTimeout
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), createWaitTimeout)
+	require.Len(t, collection, 1)
+
+	// The resource is still deleted once the deadline has passed
+	err = DestroyAllResources(ctx, client, map[string]interface{}{"config": collection}, nil)
+	require.NoError(t, err)
+	require.Empty(t, created)
+}
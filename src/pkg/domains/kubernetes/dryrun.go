@@ -0,0 +1,79 @@
+package kube
+
+import (
+	"context"
+	"errors"
+
+	"github.com/defenseunicorns/lula/src/pkg/common/network"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/e2e-framework/klient"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+)
+
+// createFunc creates an object in the cluster, e.g. klient's Resources().Create
+type createFunc func(ctx context.Context, obj k8s.Object, opts ...resources.CreateOption) error
+
+// DryRunFromManifest submits the resources in the manifest string with
+// server-side dry-run, and returns the admission outcome of each
+func DryRunFromManifest(ctx context.Context, client klient.Client, resourceBytes []byte) ([]map[string]interface{}, error) {
+	objArray, err := readResourcesFromYaml(resourceBytes)
+	if err != nil {
+		return nil, err
+	}
+	return dryRunResources(ctx, client.Resources().Create, objArray)
+}
+
+// DryRunFromFile submits the resources in a file with server-side dry-run
+func DryRunFromFile(ctx context.Context, client klient.Client, resourceFile string) ([]map[string]interface{}, error) {
+	resourceBytes, err := network.Fetch(resourceFile)
+	if err != nil {
+		return nil, err
+	}
+	return DryRunFromManifest(ctx, client, resourceBytes)
+}
+
+// dryRunResources creates each object with server-side dry-run. An object that
+// is admitted is returned as "object", after any mutations by admission
+// controllers. An object that is rejected returns the "status-code", "reason"
+// and "message" of the rejection. Errors that aren't from the API server, e.g.
+// connection errors, are returned.
+func dryRunResources(ctx context.Context, create createFunc, objs []unstructured.Unstructured) ([]map[string]interface{}, error) {
+	outcomes := make([]map[string]interface{}, 0, len(objs))
+	var errs error
+
+	for i := range objs {
+		obj := &objs[i]
+		outcome := map[string]interface{}{
+			"apiVersion": obj.GetAPIVersion(),
+			"kind":       obj.GetKind(),
+			"name":       obj.GetName(),
+			"namespace":  obj.GetNamespace(),
+		}
+
+		err := create(ctx, obj, func(opts *metav1.CreateOptions) {
+			opts.DryRun = []string{metav1.DryRunAll}
+		})
+		var status apierrors.APIStatus
+		switch {
+		case err == nil:
+			object := []map[string]interface{}{obj.Object}
+			cleanResources(&object)
+			outcome["allowed"] = true
+			outcome["object"] = object[0]
+		case errors.As(err, &status):
+			outcome["allowed"] = false
+			outcome["status-code"] = int(status.Status().Code)
+			outcome["reason"] = string(status.Status().Reason)
+			outcome["message"] = status.Status().Message
+		default:
+			errs = errors.Join(errs, err)
+			continue
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, errs
+}
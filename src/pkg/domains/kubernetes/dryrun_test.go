@@ -0,0 +1,108 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+)
+
+// admissionForTest admits pods labeled compliant, adding a mutation, and
+// rejects the rest
+func admissionForTest(_ context.Context, obj k8s.Object, opts ...resources.CreateOption) error {
+	createOptions := &metav1.CreateOptions{}
+	for _, fn := range opts {
+		fn(createOptions)
+	}
+	if len(createOptions.DryRun) != 1 || createOptions.DryRun[0] != metav1.DryRunAll {
+		return errors.New("not a dry-run")
+	}
+	if obj.GetName() == "unreachable" {
+		return errors.New("connection refused")
+	}
+	if obj.GetLabels()["compliant"] != "true" {
+		return apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, obj.GetName(), errors.New("admission webhook denied the request"))
+	}
+
+	u := obj.(*unstructured.Unstructured)
+	u.SetAnnotations(map[string]string{"mutated": "true"})
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "test"}})
+	return nil
+}
+
+func TestDryRunResources(t *testing.T) {
+	manifest := []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: compliant
+  namespace: app
+  labels:
+    compliant: "true"
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: privileged
+  namespace: app
+`)
+	objs, err := readResourcesFromYaml(manifest)
+	require.NoError(t, err)
+
+	outcomes, err := dryRunResources(context.Background(), admissionForTest, objs)
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+
+	admitted := outcomes[0]
+	require.Equal(t, true, admitted["allowed"])
+	require.Equal(t, "compliant", admitted["name"])
+	object := &unstructured.Unstructured{Object: admitted["object"].(map[string]interface{})}
+	require.Equal(t, "true", object.GetAnnotations()["mutated"])
+	require.Nil(t, object.GetManagedFields())
+
+	rejected := outcomes[1]
+	require.Equal(t, map[string]interface{}{
+		"apiVersion":  "v1",
+		"kind":        "Pod",
+		"name":        "privileged",
+		"namespace":   "app",
+		"allowed":     false,
+		"status-code": 403,
+		"reason":      "Forbidden",
+		"message":     `pods "privileged" is forbidden: admission webhook denied the request`,
+	}, rejected)
+
+	t.Run("connection error", func(t *testing.T) {
+		objs, err := readResourcesFromYaml([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: unreachable\n"))
+		require.NoError(t, err)
+		outcomes, err := dryRunResources(context.Background(), admissionForTest, objs)
+		require.Error(t, err)
+		require.Empty(t, outcomes)
+	})
+}
+
+func TestCreateKubernetesDomainDryRun(t *testing.T) {
+	dryRun := CreateResource{Name: "dry", Manifest: "test", DryRun: true}
+	create := CreateResource{Name: "create", Manifest: "test"}
+
+	d, err := CreateKubernetesDomain(&KubernetesSpec{CreateResources: []CreateResource{dryRun}})
+	require.NoError(t, err)
+	require.False(t, d.IsExecutable())
+
+	d, err = CreateKubernetesDomain(&KubernetesSpec{CreateResources: []CreateResource{dryRun, create}})
+	require.NoError(t, err)
+	require.True(t, d.IsExecutable())
+
+	_, err = CreateKubernetesDomain(&KubernetesSpec{CreateResources: []CreateResource{{Name: "dry", Namespace: "test", Manifest: "test", DryRun: true}}})
+	require.Error(t, err)
+
+	created := createdOnly(map[string]interface{}{"dry": nil, "create": nil}, []CreateResource{dryRun, create})
+	require.Equal(t, map[string]interface{}{"create": nil}, created)
+}
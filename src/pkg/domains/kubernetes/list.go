@@ -0,0 +1,151 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+// defaultPageSize is the number of resources listed per request
+const defaultPageSize int64 = 500
+
+// ErrTooManyItems is returned when a resource rule matches more than its max-items
+var ErrTooManyItems = errors.New("too many items")
+
+// listResources pages through the resources matching opts, appending them to
+// collection after pruning. Pages are requested until the list is complete, or
+// the rule's max-items is exceeded, which returns ErrTooManyItems.
+func listResources(ctx context.Context, client dynamic.ResourceInterface, opts metav1.ListOptions, rule *ResourceRule, collection []map[string]interface{}) ([]map[string]interface{}, error) {
+	opts.Limit = rule.PageSize
+	if opts.Limit == 0 {
+		opts.Limit = defaultPageSize
+	}
+
+	for {
+		list, err := client.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			if rule.MaxItems > 0 && len(collection) >= rule.MaxItems {
+				return nil, fmt.Errorf("%w: more than %d %s matched, narrow the resource rule with namespaces or selectors, or increase max-items", ErrTooManyItems, rule.MaxItems, rule.Resource)
+			}
+			collection = append(collection, pruneResource(item.Object, rule.KeepFields))
+		}
+
+		opts.Continue = list.GetContinue()
+		if opts.Continue == "" {
+			return collection, nil
+		}
+	}
+}
+
+// pathSegment is a field in a keep-fields path, and whether it's an array
+// whose every item is kept, e.g. containers[*]
+type pathSegment struct {
+	key  string
+	each bool
+}
+
+// parseFieldPath parses a keep-fields path, e.g. .spec.containers[*].image
+func parseFieldPath(path string) ([]pathSegment, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "."), ".")
+	segments := make([]pathSegment, 0, len(parts))
+	for _, part := range parts {
+		segment := pathSegment{key: part}
+		if strings.HasSuffix(part, "[*]") {
+			segment = pathSegment{key: strings.TrimSuffix(part, "[*]"), each: true}
+		}
+		if segment.key == "" || strings.ContainsAny(segment.key, "[]") {
+			return nil, fmt.Errorf("invalid keep-fields path %q", path)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// pruneResource removes metadata.managedFields from the resource and, if paths
+// are specified, every field that isn't in the paths. The apiVersion, kind,
+// name and namespace are always kept, so the resource can be identified.
+func pruneResource(resource map[string]interface{}, paths []string) map[string]interface{} {
+	if metadata, ok := resource["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+	}
+	if len(paths) == 0 {
+		return resource
+	}
+
+	pruned := make(map[string]interface{})
+	for _, path := range []string{".apiVersion", ".kind", ".metadata.name", ".metadata.namespace"} {
+		segments, _ := parseFieldPath(path)
+		keepPath(resource, pruned, segments)
+	}
+	for _, path := range paths {
+		// paths are validated with the spec
+		segments, err := parseFieldPath(path)
+		if err != nil {
+			continue
+		}
+		keepPath(resource, pruned, segments)
+	}
+	return pruned
+}
+
+// keepPath copies the value at the path from src to dst
+func keepPath(src, dst map[string]interface{}, segments []pathSegment) {
+	segment := segments[0]
+	value, ok := src[segment.key]
+	if !ok {
+		return
+	}
+	last := len(segments) == 1
+
+	if segment.each {
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		kept, _ := dst[segment.key].([]interface{})
+		if len(kept) != len(items) {
+			kept = make([]interface{}, len(items))
+		}
+		for i, item := range items {
+			if last {
+				kept[i] = item
+				continue
+			}
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			keptMap, _ := kept[i].(map[string]interface{})
+			if keptMap == nil {
+				keptMap = make(map[string]interface{})
+				kept[i] = keptMap
+			}
+			keepPath(itemMap, keptMap, segments[1:])
+		}
+		dst[segment.key] = kept
+		return
+	}
+
+	if last {
+		dst[segment.key] = value
+		return
+	}
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	keptMap, _ := dst[segment.key].(map[string]interface{})
+	if keptMap == nil {
+		keptMap = make(map[string]interface{})
+		dst[segment.key] = keptMap
+	}
+	keepPath(valueMap, keptMap, segments[1:])
+}
@@ -0,0 +1,154 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// pagedClient lists count pods in pages of the requested limit, recording the
+// limits requested. The fake dynamic client doesn't support pagination.
+type pagedClient struct {
+	dynamic.ResourceInterface
+	count  int
+	limits []int64
+}
+
+func (p *pagedClient) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	p.limits = append(p.limits, opts.Limit)
+
+	start := 0
+	if opts.Continue != "" {
+		if _, err := fmt.Sscanf(opts.Continue, "%d", &start); err != nil {
+			return nil, err
+		}
+	}
+	end := min(start+int(opts.Limit), p.count)
+
+	list := &unstructured.UnstructuredList{}
+	for i := start; i < end; i++ {
+		pod := newObject("v1", "Pod", "app", fmt.Sprintf("pod-%d", i), map[string]string{"app": "web"})
+		pod.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubelet"}})
+		pod.Object["spec"] = map[string]interface{}{
+			"nodeName": "node-1",
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx", "args": []interface{}{"-g"}},
+				map[string]interface{}{"name": "proxy", "image": "envoy"},
+			},
+		}
+		list.Items = append(list.Items, *pod)
+	}
+	if end < p.count {
+		list.SetContinue(fmt.Sprintf("%d", end))
+	}
+	return list, nil
+}
+
+func TestListResources(t *testing.T) {
+	t.Run("default page size", func(t *testing.T) {
+		client := &pagedClient{count: 1200}
+		collection, err := listResources(context.Background(), client, metav1.ListOptions{}, &ResourceRule{Version: "v1", Resource: "pods"}, nil)
+		require.NoError(t, err)
+		require.Len(t, collection, 1200)
+		require.Equal(t, []int64{500, 500, 500}, client.limits)
+		require.Equal(t, "pod-1199", collection[1199]["metadata"].(map[string]interface{})["name"])
+		require.NotContains(t, collection[0]["metadata"], "managedFields")
+	})
+
+	t.Run("page size", func(t *testing.T) {
+		client := &pagedClient{count: 25}
+		collection, err := listResources(context.Background(), client, metav1.ListOptions{}, &ResourceRule{Version: "v1", Resource: "pods", PageSize: 10}, nil)
+		require.NoError(t, err)
+		require.Len(t, collection, 25)
+		require.Equal(t, []int64{10, 10, 10}, client.limits)
+	})
+
+	t.Run("max items", func(t *testing.T) {
+		rule := &ResourceRule{Version: "v1", Resource: "pods", PageSize: 10, MaxItems: 20}
+		client := &pagedClient{count: 25}
+		_, err := listResources(context.Background(), client, metav1.ListOptions{}, rule, nil)
+		require.ErrorIs(t, err, ErrTooManyItems)
+		// the remaining page isn't requested
+		require.Equal(t, []int64{10, 10, 10}, client.limits)
+
+		// items from previous namespaces count towards the maximum
+		rule.MaxItems = 30
+		_, err = listResources(context.Background(), &pagedClient{count: 25}, metav1.ListOptions{}, rule, make([]map[string]interface{}, 10))
+		require.ErrorIs(t, err, ErrTooManyItems)
+
+		rule.MaxItems = 25
+		collection, err := listResources(context.Background(), &pagedClient{count: 25}, metav1.ListOptions{}, rule, nil)
+		require.NoError(t, err)
+		require.Len(t, collection, 25)
+	})
+
+	t.Run("keep fields", func(t *testing.T) {
+		collection, err := listResources(context.Background(), &pagedClient{count: 1}, metav1.ListOptions{}, &ResourceRule{
+			Version:    "v1",
+			Resource:   "pods",
+			KeepFields: []string{".metadata.labels", "spec.containers[*].image"},
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      "pod-0",
+				"namespace": "app",
+				"labels":    map[string]interface{}{"app": "web"},
+			},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"image": "nginx"},
+					map[string]interface{}{"image": "envoy"},
+				},
+			},
+		}}, collection)
+	})
+}
+
+func TestValidateListing(t *testing.T) {
+	tests := map[string]struct {
+		rule    ResourceRule
+		wantErr bool
+	}{
+		"valid":                   {rule: ResourceRule{PageSize: 100, MaxItems: 1000, KeepFields: []string{".spec", ".spec.containers[*].securityContext"}}},
+		"negative page size":      {rule: ResourceRule{PageSize: -1}, wantErr: true},
+		"negative max items":      {rule: ResourceRule{MaxItems: -1}, wantErr: true},
+		"empty path segment":      {rule: ResourceRule{KeepFields: []string{".spec..containers"}}, wantErr: true},
+		"unsupported array index": {rule: ResourceRule{KeepFields: []string{".spec.containers[0].image"}}, wantErr: true},
+		"keep fields with field":  {rule: ResourceRule{Name: "test", KeepFields: []string{".data"}, Field: &Field{Jsonpath: ".data.test"}}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.rule.validateListing()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateListing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPruneResource(t *testing.T) {
+	resource := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "test", "uid": "1234", "managedFields": []interface{}{}},
+		"data":       map[string]interface{}{"a": "1", "b": "2"},
+	}
+
+	// the whole metadata is kept, apart from managedFields
+	pruned := pruneResource(resource, []string{".metadata", ".data.a", ".missing.field"})
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "test", "uid": "1234"},
+		"data":       map[string]interface{}{"a": "1"},
+	}, pruned)
+}
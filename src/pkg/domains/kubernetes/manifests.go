@@ -0,0 +1,190 @@
+package kube
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/defenseunicorns/lula/src/pkg/common/network"
+)
+
+// manifestExtensions are the file extensions read when a manifest path is a directory
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// NewManifestCluster returns a Cluster that serves resource queries from the
+// objects instead of a live cluster. Resource types are derived from the object
+// kinds, e.g. NetworkPolicy is served as networkpolicies. The rules are used
+// to register resource types that have no objects, so they return empty lists.
+func NewManifestCluster(objects []*unstructured.Unstructured, rules []*ResourceRule) (*Cluster, error) {
+	listKinds := map[schema.GroupVersionResource]string{
+		// namespaces are always listable, for namespace label selectors
+		{Version: "v1", Resource: "namespaces"}: "NamespaceList",
+	}
+	for _, rule := range rules {
+		gvr := schema.GroupVersionResource{Group: rule.Group, Version: rule.Version, Resource: rule.Resource}
+		listKinds[gvr] = "List"
+	}
+	for _, obj := range objects {
+		gvr, _ := meta.UnsafeGuessKindToResource(obj.GroupVersionKind())
+		listKinds[gvr] = obj.GetKind() + "List"
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	var errs error
+	for _, obj := range objects {
+		if err := client.Tracker().Add(obj); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error adding %s %s: %w", obj.GetKind(), objectKey(obj), err))
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	return &Cluster{dynamicClient: client}, nil
+}
+
+// loadManifests reads the objects from the manifest paths. Paths may be files,
+// directories (read recursively), globs or URLs, and files may contain multiple
+// YAML documents or JSON objects. Relative paths are resolved from workDir.
+func loadManifests(paths []string, workDir string) ([]*unstructured.Unstructured, error) {
+	objects := make([]*unstructured.Unstructured, 0)
+	var errs error
+
+	for _, path := range paths {
+		files, err := manifestFiles(path, workDir)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error reading manifests %s: %w", path, err))
+			continue
+		}
+		for _, file := range files {
+			var b []byte
+			if strings.Contains(file, "://") {
+				b, err = network.Fetch(file)
+			} else {
+				b, err = os.ReadFile(file)
+			}
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("error reading manifest %s: %w", file, err))
+				continue
+			}
+			objs, err := readManifest(b)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("error parsing manifest %s: %w", file, err))
+				continue
+			}
+			objects = append(objects, objs...)
+		}
+	}
+
+	return objects, errs
+}
+
+// manifestFiles expands a manifest path into the files to read
+func manifestFiles(path, workDir string) ([]string, error) {
+	if strings.Contains(path, "://") {
+		return []string{path}, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, errors.New("no files matched")
+		}
+		return matches, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(current string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && isManifestFile(current) {
+			files = append(files, current)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func isManifestFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range manifestExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// readManifest parses the objects in a YAML or JSON manifest. Empty documents
+// are skipped, and List objects are expanded into their items.
+func readManifest(b []byte) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+	objects := make([]*unstructured.Unstructured, 0)
+
+	for {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 || bytes.Equal(doc, []byte("null")) || bytes.Equal(doc, []byte("{}")) {
+			continue
+		}
+
+		// decode the same way as objects from a live cluster, e.g. numbers are int64
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(doc); err != nil {
+			return nil, err
+		}
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+		err := obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
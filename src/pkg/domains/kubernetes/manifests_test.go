@@ -0,0 +1,144 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestGetResourcesFromManifests(t *testing.T) {
+	ctx := context.WithValue(context.Background(), types.LulaValidationWorkDir, "testdata")
+
+	tests := map[string]struct {
+		manifests []string
+		rule      *ResourceRule
+		want      []string
+	}{
+		"directory": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Group: "apps", Version: "v1", Resource: "deployments"},
+			want:      []string{"app/web", "jobs/worker"},
+		},
+		"single file": {
+			manifests: []string{"manifests/app.yaml"},
+			rule:      &ResourceRule{Group: "apps", Version: "v1", Resource: "deployments"},
+			want:      []string{"app/web"},
+		},
+		"glob": {
+			manifests: []string{"manifests/*/*.json"},
+			rule:      &ResourceRule{Version: "v1", Resource: "configmaps"},
+			want:      []string{"app/settings"},
+		},
+		"namespaces": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Group: "apps", Version: "v1", Resource: "deployments", Namespaces: []string{"jobs"}},
+			want:      []string{"jobs/worker"},
+		},
+		"label selector": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Group: "apps", Version: "v1", Resource: "deployments", LabelSelector: "app=web"},
+			want:      []string{"app/web"},
+		},
+		"namespace label selector": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Group: "apps", Version: "v1", Resource: "deployments", NamespaceLabelSelector: "tier=app"},
+			want:      []string{"app/web"},
+		},
+		"irregular plural": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"},
+			want:      []string{"app/default-deny"},
+		},
+		"no matching objects": {
+			manifests: []string{"manifests"},
+			rule:      &ResourceRule{Version: "v1", Resource: "pods"},
+			want:      []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := CreateKubernetesDomain(&KubernetesSpec{
+				Manifests: tt.manifests,
+				Resources: []Resource{{Name: "test", ResourceRule: tt.rule}},
+			})
+			require.NoError(t, err)
+			require.False(t, d.IsExecutable())
+
+			drs, err := d.GetResources(ctx)
+			require.NoError(t, err)
+
+			got := make([]string, 0)
+			for _, item := range drs["test"].([]map[string]interface{}) {
+				obj := unstructured.Unstructured{Object: item}
+				got = append(got, obj.GetNamespace()+"/"+obj.GetName())
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	t.Run("named resource with field", func(t *testing.T) {
+		d, err := CreateKubernetesDomain(&KubernetesSpec{
+			Manifests: []string{"manifests"},
+			Resources: []Resource{{Name: "test", ResourceRule: &ResourceRule{
+				Name:       "web",
+				Group:      "apps",
+				Version:    "v1",
+				Resource:   "deployments",
+				Namespaces: []string{"app"},
+			}}},
+		})
+		require.NoError(t, err)
+
+		drs, err := d.GetResources(ctx)
+		require.NoError(t, err)
+		deployment := drs["test"].(map[string]interface{})
+		require.Equal(t, int64(2), deployment["spec"].(map[string]interface{})["replicas"])
+	})
+}
+
+func TestCreateKubernetesDomainWithManifests(t *testing.T) {
+	rule := &ResourceRule{Version: "v1", Resource: "pods"}
+
+	tests := map[string]*KubernetesSpec{
+		"with create-resources": {
+			Manifests:       []string{"manifests"},
+			Resources:       []Resource{{Name: "test", ResourceRule: rule}},
+			CreateResources: []CreateResource{{Name: "test", Manifest: "test"}},
+		},
+		"with wait": {
+			Manifests: []string{"manifests"},
+			Resources: []Resource{{Name: "test", ResourceRule: rule}},
+			Wait:      &Wait{Name: "test", Version: "v1", Resource: "pods"},
+		},
+		"with field selector": {
+			Manifests: []string{"manifests"},
+			Resources: []Resource{{Name: "test", ResourceRule: &ResourceRule{Version: "v1", Resource: "pods", FieldSelector: "status.phase=Running"}}},
+		},
+	}
+
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CreateKubernetesDomain(spec)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	t.Run("missing kind", func(t *testing.T) {
+		_, err := readManifest([]byte("apiVersion: v1\nmetadata:\n  name: test\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("duplicate objects", func(t *testing.T) {
+		objects, err := readManifest([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: test\n---\napiVersion: v1\nkind: Pod\nmetadata:\n  name: test\n"))
+		require.NoError(t, err)
+		_, err = NewManifestCluster(objects, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,278 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+const defaultExecTimeout = "30s"
+
+// newExecutor creates the executor for exec requests, overridden in tests
+var newExecutor = func(config *rest.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+	return remotecommand.NewSPDYExecutor(config, method, url)
+}
+
+// LogsRule selects the container logs to collect
+type LogsRule struct {
+	// Name of the pod, only one of name or label-selector may be specified
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// LabelSelector selects the pods to collect logs from
+	LabelSelector string `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	Namespace     string `json:"namespace" yaml:"namespace"`
+	// Container to collect logs from, defaults to every container in the pod
+	Container string `json:"container,omitempty" yaml:"container,omitempty"`
+	// TailLines limits the logs to the last lines
+	TailLines *int64 `json:"tail-lines,omitempty" yaml:"tail-lines,omitempty"`
+	// Since limits the logs to a duration before now, e.g. 1h
+	Since string `json:"since,omitempty" yaml:"since,omitempty"`
+	// Previous collects the logs of the previous instance of the container
+	Previous bool `json:"previous,omitempty" yaml:"previous,omitempty"`
+}
+
+// ExecRule is a command to run in a pod
+type ExecRule struct {
+	// Name of the pod, only one of name or label-selector may be specified
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// LabelSelector selects the pod to run the command in, the first running pod is used
+	LabelSelector string `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	Namespace     string `json:"namespace" yaml:"namespace"`
+	// Container to run the command in, defaults to the first container in the pod
+	Container string   `json:"container,omitempty" yaml:"container,omitempty"`
+	Command   []string `json:"command" yaml:"command"`
+	// Timeout for the command, defaults to 30s
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+func (l LogsRule) validate() error {
+	if err := validatePodSelection(l.Name, l.LabelSelector, l.Namespace); err != nil {
+		return fmt.Errorf("logs %w", err)
+	}
+	if l.TailLines != nil && *l.TailLines < 0 {
+		return errors.New("logs tail-lines cannot be negative")
+	}
+	if l.Since != "" {
+		if _, err := time.ParseDuration(l.Since); err != nil {
+			return fmt.Errorf("invalid logs since: %s", l.Since)
+		}
+	}
+	return nil
+}
+
+func (e ExecRule) validate() error {
+	if err := validatePodSelection(e.Name, e.LabelSelector, e.Namespace); err != nil {
+		return fmt.Errorf("exec %w", err)
+	}
+	if len(e.Command) == 0 {
+		return errors.New("exec command cannot be empty")
+	}
+	if e.Timeout != "" {
+		if _, err := time.ParseDuration(e.Timeout); err != nil {
+			return fmt.Errorf("invalid exec timeout: %s", e.Timeout)
+		}
+	}
+	return nil
+}
+
+func validatePodSelection(name, labelSelector, namespace string) error {
+	if namespace == "" {
+		return errors.New("namespace cannot be empty")
+	}
+	if name == "" && labelSelector == "" {
+		return errors.New("name or label-selector must be specified")
+	}
+	if name != "" && labelSelector != "" {
+		return errors.New("name and label-selector cannot both be specified")
+	}
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return fmt.Errorf("invalid label-selector: %w", err)
+	}
+	return nil
+}
+
+// GetPodLogs returns the logs of each selected container, as a list of
+// objects with the namespace, pod, container and log
+func GetPodLogs(ctx context.Context, cluster *Cluster, rule *LogsRule) ([]map[string]interface{}, error) {
+	pods, err := selectPods(ctx, cluster, rule.Name, rule.LabelSelector, rule.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container: rule.Container,
+		TailLines: rule.TailLines,
+		Previous:  rule.Previous,
+	}
+	if rule.Since != "" {
+		since, err := time.ParseDuration(rule.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logs since: %s", rule.Since)
+		}
+		seconds := int64(since.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+
+	collection := make([]map[string]interface{}, 0)
+	var errs error
+	for _, pod := range pods {
+		containers := []string{rule.Container}
+		if rule.Container == "" {
+			containers = containerNames(pod)
+		}
+		for _, container := range containers {
+			containerOpts := opts.DeepCopy()
+			containerOpts.Container = container
+			log, err := readLogs(ctx, cluster, pod, containerOpts)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("error getting logs for %s/%s container %s: %w", pod.Namespace, pod.Name, container, err))
+				continue
+			}
+			collection = append(collection, map[string]interface{}{
+				"namespace": pod.Namespace,
+				"pod":       pod.Name,
+				"container": container,
+				"log":       log,
+			})
+		}
+	}
+
+	return collection, errs
+}
+
+func readLogs(ctx context.Context, cluster *Cluster, pod corev1.Pod, opts *corev1.PodLogOptions) (string, error) {
+	stream, err := cluster.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	b, err := io.ReadAll(stream)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ExecInPod runs the command in the selected pod, returning the namespace, pod,
+// container, stdout, stderr and exit-code. A non-zero exit code isn't an error,
+// so it can be evaluated by the policy.
+func ExecInPod(ctx context.Context, cluster *Cluster, rule *ExecRule) (map[string]interface{}, error) {
+	pods, err := selectPods(ctx, cluster, rule.Name, rule.LabelSelector, rule.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := runningPod(pods)
+	if err != nil {
+		return nil, err
+	}
+
+	container := rule.Container
+	if container == "" {
+		names := containerNames(pod)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("pod %s/%s has no containers", pod.Namespace, pod.Name)
+		}
+		container = names[0]
+	}
+
+	timeoutString := rule.Timeout
+	if timeoutString == "" {
+		timeoutString = defaultExecTimeout
+	}
+	timeout, err := time.ParseDuration(timeoutString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec timeout: %s", timeoutString)
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	execURL := podExecURL(cluster, pod, &corev1.PodExecOptions{
+		Container: container,
+		Command:   rule.Command,
+		Stdout:    true,
+		Stderr:    true,
+	})
+	executor, err := newExecutor(cluster.config, "POST", execURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := 0
+	err = executor.StreamWithContext(execCtx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		var exitErr exec.ExitError
+		if !errors.As(err, &exitErr) || !exitErr.Exited() {
+			return nil, fmt.Errorf("error executing command in %s/%s container %s: %w", pod.Namespace, pod.Name, container, err)
+		}
+		exitCode = exitErr.ExitStatus()
+	}
+
+	return map[string]interface{}{
+		"namespace": pod.Namespace,
+		"pod":       pod.Name,
+		"container": container,
+		"stdout":    stdout.String(),
+		"stderr":    stderr.String(),
+		"exit-code": exitCode,
+	}, nil
+}
+
+// podExecURL returns the URL of the exec subresource of the pod
+func podExecURL(cluster *Cluster, pod corev1.Pod, opts *corev1.PodExecOptions) *url.URL {
+	return cluster.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(opts, scheme.ParameterCodec).
+		URL()
+}
+
+// selectPods returns the named pod, or the pods matching the label selector
+func selectPods(ctx context.Context, cluster *Cluster, name, labelSelector, namespace string) ([]corev1.Pod, error) {
+	client := cluster.clientset.CoreV1().Pods(namespace)
+	if name != "" {
+		pod, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// runningPod returns the first running pod
+func runningPod(pods []corev1.Pod) (corev1.Pod, error) {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod, nil
+		}
+	}
+	return corev1.Pod{}, errors.New("no running pod found")
+}
+
+func containerNames(pod corev1.Pod) []string {
+	names := make([]string, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
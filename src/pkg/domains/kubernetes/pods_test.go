@@ -0,0 +1,220 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor writes the output and returns the err of a command
+type fakeExecutor struct {
+	url    *url.URL
+	stdout string
+	stderr string
+	err    error
+}
+
+func (f *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return f.StreamWithContext(context.Background(), options)
+}
+
+func (f *fakeExecutor) StreamWithContext(_ context.Context, options remotecommand.StreamOptions) error {
+	fmt.Fprint(options.Stdout, f.stdout)
+	fmt.Fprint(options.Stderr, f.stderr)
+	return f.err
+}
+
+// podClientset is a fake clientset with a REST client for the cluster config,
+// as the fake clientset has none to build exec requests with
+type podClientset struct {
+	*fake.Clientset
+	restClient rest.Interface
+}
+
+func (c *podClientset) CoreV1() corev1client.CoreV1Interface {
+	return &podCoreV1{CoreV1Interface: c.Clientset.CoreV1(), restClient: c.restClient}
+}
+
+type podCoreV1 struct {
+	corev1client.CoreV1Interface
+	restClient rest.Interface
+}
+
+func (c *podCoreV1) RESTClient() rest.Interface {
+	return c.restClient
+}
+
+func newPodClusterForTest(t *testing.T, pods ...*corev1.Pod) *Cluster {
+	clientset := fake.NewSimpleClientset()
+	for _, pod := range pods {
+		_ = clientset.Tracker().Add(pod)
+	}
+	config := &rest.Config{Host: "https://cluster.example.com"}
+	restClientset, err := kubernetes.NewForConfig(config)
+	require.NoError(t, err)
+	return &Cluster{
+		clientset: &podClientset{Clientset: clientset, restClient: restClientset.CoreV1().RESTClient()},
+		config:    config,
+	}
+}
+
+func newTestPod(namespace, name string, phase corev1.PodPhase, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+	}
+	return pod
+}
+
+func TestGetPodLogs(t *testing.T) {
+	cluster := newPodClusterForTest(t,
+		newTestPod("app", "web-1", corev1.PodRunning, "web", "proxy"),
+		newTestPod("app", "web-2", corev1.PodRunning, "web", "proxy"),
+	)
+
+	t.Run("all containers of selected pods", func(t *testing.T) {
+		tail := int64(10)
+		logs, err := GetPodLogs(context.Background(), cluster, &LogsRule{LabelSelector: "app=web", Namespace: "app", TailLines: &tail, Since: "1h"})
+		require.NoError(t, err)
+		require.Len(t, logs, 4)
+		for _, log := range logs {
+			// the fake clientset always returns "fake logs"
+			require.Equal(t, "fake logs", log["log"])
+		}
+	})
+
+	t.Run("container of named pod", func(t *testing.T) {
+		logs, err := GetPodLogs(context.Background(), cluster, &LogsRule{Name: "web-1", Namespace: "app", Container: "proxy", Previous: true})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{
+			{"namespace": "app", "pod": "web-1", "container": "proxy", "log": "fake logs"},
+		}, logs)
+	})
+
+	t.Run("missing pod", func(t *testing.T) {
+		_, err := GetPodLogs(context.Background(), cluster, &LogsRule{Name: "missing", Namespace: "app"})
+		require.Error(t, err)
+	})
+}
+
+func TestExecInPod(t *testing.T) {
+	cluster := newPodClusterForTest(t,
+		newTestPod("app", "web-0", corev1.PodPending, "web"),
+		newTestPod("app", "web-1", corev1.PodRunning, "web", "proxy"),
+	)
+
+	tests := map[string]struct {
+		rule     *ExecRule
+		executor *fakeExecutor
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		"first running pod": {
+			rule:     &ExecRule{LabelSelector: "app=web", Namespace: "app", Command: []string{"cat", "/proc/sys/crypto/fips_enabled"}},
+			executor: &fakeExecutor{stdout: "1\n"},
+			want:     map[string]interface{}{"namespace": "app", "pod": "web-1", "container": "web", "stdout": "1\n", "stderr": "", "exit-code": 0},
+		},
+		"non-zero exit code": {
+			rule:     &ExecRule{Name: "web-1", Namespace: "app", Container: "proxy", Command: []string{"ls", "/missing"}},
+			executor: &fakeExecutor{stderr: "No such file or directory", err: exec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}},
+			want:     map[string]interface{}{"namespace": "app", "pod": "web-1", "container": "proxy", "stdout": "", "stderr": "No such file or directory", "exit-code": 2},
+		},
+		"stream error": {
+			rule:     &ExecRule{Name: "web-1", Namespace: "app", Command: []string{"true"}},
+			executor: &fakeExecutor{err: errors.New("connection refused")},
+			wantErr:  true,
+		},
+		"no running pod": {
+			rule:     &ExecRule{Name: "web-0", Namespace: "app", Command: []string{"true"}},
+			executor: &fakeExecutor{},
+			wantErr:  true,
+		},
+	}
+
+	original := newExecutor
+	t.Cleanup(func() { newExecutor = original })
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			newExecutor = func(_ *rest.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+				require.Equal(t, "POST", method)
+				tt.executor.url = url
+				return tt.executor, nil
+			}
+
+			got, err := ExecInPod(context.Background(), cluster, tt.rule)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.True(t, strings.HasPrefix(tt.executor.url.String(), "https://cluster.example.com/api/v1/namespaces/app/pods/web-1/exec?"))
+			require.Equal(t, tt.rule.Command, tt.executor.url.Query()["command"])
+		})
+	}
+}
+
+func TestCreateKubernetesDomainPodRules(t *testing.T) {
+	tests := map[string]struct {
+		resource       Resource
+		wantExecutable bool
+		wantErr        bool
+	}{
+		"logs": {
+			resource: Resource{Name: "logs", Logs: &LogsRule{LabelSelector: "app=web", Namespace: "app"}},
+		},
+		"exec": {
+			resource:       Resource{Name: "fips", Exec: &ExecRule{Name: "web", Namespace: "app", Command: []string{"true"}}},
+			wantExecutable: true,
+		},
+		"logs and resource rule": {
+			resource: Resource{Name: "logs", Logs: &LogsRule{Name: "web", Namespace: "app"}, ResourceRule: &ResourceRule{Version: "v1", Resource: "pods"}},
+			wantErr:  true,
+		},
+		"logs without namespace": {
+			resource: Resource{Name: "logs", Logs: &LogsRule{Name: "web"}},
+			wantErr:  true,
+		},
+		"logs with invalid since": {
+			resource: Resource{Name: "logs", Logs: &LogsRule{Name: "web", Namespace: "app", Since: "yesterday"}},
+			wantErr:  true,
+		},
+		"exec without command": {
+			resource: Resource{Name: "fips", Exec: &ExecRule{Name: "web", Namespace: "app"}},
+			wantErr:  true,
+		},
+		"exec with name and selector": {
+			resource: Resource{Name: "fips", Exec: &ExecRule{Name: "web", LabelSelector: "app=web", Namespace: "app", Command: []string{"true"}}},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := CreateKubernetesDomain(&KubernetesSpec{Resources: []Resource{tt.resource}})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantExecutable, d.IsExecutable())
+		})
+	}
+}
@@ -6,52 +6,131 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/defenseunicorns/lula/src/types"
 )
 
 // QueryCluster() requires context and a Payload as input and returns []unstructured.Unstructured
 // This function is used to query the cluster for all resources required for processing
+// Resources with contexts are queried in the cluster of each context instead, and keyed by context name.
 func QueryCluster(ctx context.Context, cluster *Cluster, resources []Resource) (map[string]interface{}, error) {
-	if cluster == nil {
-		return nil, fmt.Errorf("cluster is nil")
-	}
-
 	collections := make(map[string]interface{}, 0)
 	var errs error
 
 	for _, resource := range resources {
-		collection, err := GetResourcesDynamically(ctx, cluster, resource.ResourceRule)
-		// capture error but continue with other resources
-		if err != nil {
-			errs = errors.Join(errs, err)
+		if len(resource.Contexts) == 0 {
+			if cluster == nil {
+				return nil, fmt.Errorf("cluster is nil")
+			}
+			collection, err := queryResource(ctx, cluster, resource)
+			// capture error but continue with other resources
+			if err != nil {
+				errs = errors.Join(errs, err)
+			}
+			collections[resource.Name] = collection
+			continue
 		}
 
-		if resource.ResourceRule.Name != "" {
-			if len(collection) > 0 {
-				collections[resource.Name] = collection[0]
-			} else {
-				// This request returned no resources
-				collections[resource.Name] = map[string]interface{}{}
+		byContext := make(map[string]interface{}, len(resource.Contexts))
+		for _, name := range resource.Contexts {
+			contextCluster, err := GetClusterForContext(name)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				byContext[name], _ = queryResource(ctx, nil, resource)
+				continue
 			}
-
-		} else {
-			if len(collection) > 0 {
-				collections[resource.Name] = collection
-			} else {
-				// This request returned no resources
-				collections[resource.Name] = []map[string]interface{}{}
+			collection, err := queryResource(ctx, contextCluster, resource)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("context %s: %w", name, err))
 			}
+			byContext[name] = collection
 		}
+		collections[resource.Name] = byContext
 	}
 
 	return collections, errs
 }
 
+// queryResource returns the named resource, or the list of resources, matching
+// the resource rule, or the logs, exec output or access decisions of the
+// resource. An empty object or list is returned if nothing matched or the
+// cluster is nil.
+func queryResource(ctx context.Context, cluster *Cluster, resource Resource) (interface{}, error) {
+	switch {
+	case resource.Logs != nil:
+		if cluster == nil {
+			return []map[string]interface{}{}, nil
+		}
+		logs, err := GetPodLogs(ctx, cluster, resource.Logs)
+		if logs == nil {
+			logs = []map[string]interface{}{}
+		}
+		return logs, err
+	case resource.Exec != nil:
+		if cluster == nil {
+			return map[string]interface{}{}, nil
+		}
+		result, err := ExecInPod(ctx, cluster, resource.Exec)
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		return result, nil
+	case resource.AccessReview != nil:
+		if cluster == nil {
+			return []map[string]interface{}{}, nil
+		}
+		return ReviewAccess(ctx, cluster, resource.AccessReview)
+	}
+
+	var collection []map[string]interface{}
+	var err error
+	if cluster != nil {
+		collection, err = cachedResources(ctx, cluster, resource.ResourceRule)
+	}
+
+	if resource.ResourceRule.Name != "" {
+		if len(collection) > 0 {
+			return collection[0], err
+		}
+		// This request returned no resources
+		return map[string]interface{}{}, err
+	}
+
+	if len(collection) > 0 {
+		return collection, err
+	}
+	// This request returned no resources
+	return []map[string]interface{}{}, err
+}
+
+// cachedResources returns the resources matching the rule from the resource
+// cache of the context, so identical rules across validations are queried
+// once. Only live clusters are cached, as they are kept for the whole run.
+func cachedResources(ctx context.Context, cluster *Cluster, rule *ResourceRule) ([]map[string]interface{}, error) {
+	cache := types.ResourceCacheFromContext(ctx)
+	if cache == nil || cluster.config == nil {
+		return GetResourcesDynamically(ctx, cluster, rule)
+	}
+	ruleKey, err := json.Marshal(rule)
+	if err != nil {
+		return GetResourcesDynamically(ctx, cluster, rule)
+	}
+
+	key := fmt.Sprintf("kubernetes %s %p %s", cluster.config.Host, cluster, ruleKey)
+	value, err := cache.Get(ctx, key, func() (interface{}, error) {
+		return GetResourcesDynamically(ctx, cluster, rule)
+	})
+	collection, _ := value.([]map[string]interface{})
+	return collection, err
+}
+
 // GetResourcesDynamically() requires a dynamic interface and processes GVR to return []map[string]interface{}
 // This function is used to query the cluster for specific subset of resources required for processing
 func GetResourcesDynamically(ctx context.Context, cluster *Cluster, resource *ResourceRule) ([]map[string]interface{}, error) {
@@ -81,7 +160,7 @@ func GetResourcesDynamically(ctx context.Context, cluster *Cluster, resource *Re
 		if err != nil {
 			return nil, err
 		}
-		item := itemObj.Object
+		item := pruneResource(itemObj.Object, resource.KeepFields)
 
 		// If field is specified, get the field data; can only occur when a single named resource is specified
 		if resource.Field != nil && resource.Field.Jsonpath != "" {
@@ -93,24 +172,48 @@ func GetResourcesDynamically(ctx context.Context, cluster *Cluster, resource *Re
 
 		collection = append(collection, item)
 	} else {
-		for _, namespace := range namespaces {
-			list, err := cluster.dynamicClient.Resource(resourceId).Namespace(namespace).
-				List(ctx, metav1.ListOptions{})
+		if resource.NamespaceLabelSelector != "" {
+			var err error
+			namespaces, err = selectNamespaces(ctx, cluster, resource.Namespaces, resource.NamespaceLabelSelector)
 			if err != nil {
 				return nil, err
 			}
+		}
 
-			for _, item := range list.Items {
-				collection = append(collection, item.Object)
+		for _, namespace := range namespaces {
+			var err error
+			collection, err = listResources(ctx, cluster.dynamicClient.Resource(resourceId).Namespace(namespace),
+				metav1.ListOptions{
+					LabelSelector: resource.LabelSelector,
+					FieldSelector: resource.FieldSelector,
+				}, resource, collection)
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
 
-	cleanResources(&collection)
-
 	return collection, nil
 }
 
+// selectNamespaces() returns the namespaces matching the label selector. If namespaces
+// are specified, only those that also match the selector are returned.
+func selectNamespaces(ctx context.Context, cluster *Cluster, namespaces []string, selector string) ([]string, error) {
+	namespaceId := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	list, err := cluster.dynamicClient.Resource(namespaceId).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("error listing namespaces: %w", err)
+	}
+
+	selected := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		if len(namespaces) == 0 || slices.Contains(namespaces, item.GetName()) {
+			selected = append(selected, item.GetName())
+		}
+	}
+	return selected, nil
+}
+
 // getFieldValue() looks up the field from a resource and returns a map[string]interface{} representation of the data
 func getFieldValue(item map[string]interface{}, field *Field) (map[string]interface{}, error) {
 	if field == nil {
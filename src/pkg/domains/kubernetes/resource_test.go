@@ -0,0 +1,225 @@
+package kube
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestGetResourcesDynamicallySelectors(t *testing.T) {
+	cluster := &Cluster{dynamicClient: newFakeDynamicClient(
+		newObject("v1", "Namespace", "", "app-1", map[string]string{"tier": "app"}),
+		newObject("v1", "Namespace", "", "app-2", map[string]string{"tier": "app"}),
+		newObject("v1", "Namespace", "", "system", map[string]string{"tier": "system"}),
+		newObject("v1", "Pod", "app-1", "web", map[string]string{"app": "web"}),
+		newObject("v1", "Pod", "app-1", "db", map[string]string{"app": "db"}),
+		newObject("v1", "Pod", "app-2", "web", map[string]string{"app": "web"}),
+		newObject("v1", "Pod", "system", "web", map[string]string{"app": "web"}),
+	)}
+
+	tests := map[string]struct {
+		rule *ResourceRule
+		want []string
+	}{
+		"all pods": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods"},
+			want: []string{"app-1/db", "app-1/web", "app-2/web", "system/web"},
+		},
+		"label selector": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods", LabelSelector: "app=web"},
+			want: []string{"app-1/web", "app-2/web", "system/web"},
+		},
+		"label selector in namespaces": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods", LabelSelector: "app in (web, db)", Namespaces: []string{"app-1"}},
+			want: []string{"app-1/db", "app-1/web"},
+		},
+		"namespace label selector": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods", LabelSelector: "app=web", NamespaceLabelSelector: "tier=app"},
+			want: []string{"app-1/web", "app-2/web"},
+		},
+		"namespace label selector and namespaces": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods", NamespaceLabelSelector: "tier=app", Namespaces: []string{"app-2", "system"}},
+			want: []string{"app-2/web"},
+		},
+		"no matching namespaces": {
+			rule: &ResourceRule{Version: "v1", Resource: "pods", NamespaceLabelSelector: "tier=missing"},
+			want: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			collection, err := GetResourcesDynamically(context.Background(), cluster, tt.rule)
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(collection))
+			for _, item := range collection {
+				obj := unstructured.Unstructured{Object: item}
+				got = append(got, obj.GetNamespace()+"/"+obj.GetName())
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestQueryClusterCache(t *testing.T) {
+	client := newFakeDynamicClient(newObject("v1", "Pod", "app", "web", nil))
+	lists := 0
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	cluster := &Cluster{dynamicClient: client, config: &rest.Config{Host: "https://cluster.example.com"}}
+	rule := &ResourceRule{Version: "v1", Resource: "pods", Namespaces: []string{"app"}}
+
+	ctx := types.WithResourceCache(context.Background(), types.NewResourceCache())
+	for _, name := range []string{"pods", "workloads"} {
+		collections, err := QueryCluster(ctx, cluster, []Resource{{Name: name, ResourceRule: rule}})
+		require.NoError(t, err)
+		require.Len(t, collections[name], 1)
+	}
+	require.Equal(t, 1, lists)
+
+	// a different rule is queried
+	_, err := QueryCluster(ctx, cluster, []Resource{{Name: "pods", ResourceRule: &ResourceRule{Version: "v1", Resource: "pods"}}})
+	require.NoError(t, err)
+	require.Equal(t, 2, lists)
+
+	// manifest clusters aren't cached
+	manifestCluster := &Cluster{dynamicClient: client}
+	for range 2 {
+		_, err := QueryCluster(ctx, manifestCluster, []Resource{{Name: "pods", ResourceRule: rule}})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, lists)
+}
+
+func TestValidateSelectors(t *testing.T) {
+	tests := map[string]struct {
+		rule    ResourceRule
+		wantErr bool
+	}{
+		"valid": {
+			rule: ResourceRule{LabelSelector: "app=web,tier notin (cache)", FieldSelector: "status.phase=Running", NamespaceLabelSelector: "env"},
+		},
+		"invalid label selector": {
+			rule:    ResourceRule{LabelSelector: "app in ("},
+			wantErr: true,
+		},
+		"invalid field selector": {
+			rule:    ResourceRule{FieldSelector: "status.phase"},
+			wantErr: true,
+		},
+		"selector with name": {
+			rule:    ResourceRule{Name: "web", LabelSelector: "app=web"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.rule.validateSelectors()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateSelectors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newFakeDynamicClient returns a fake dynamic client serving the objects
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, obj := range objects {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		gvr := schema.GroupVersionResource{Group: gvk.Group, Version: gvk.Version, Resource: pluralize(gvk.Kind)}
+		listKinds[gvr] = gvk.Kind + "List"
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func newObject(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func pluralize(kind string) string {
+	switch kind {
+	case "NetworkPolicy":
+		return "networkpolicies"
+	default:
+		return strings.ToLower(kind) + "s"
+	}
+}
+
+func TestQueryClusterContexts(t *testing.T) {
+	contextClustersMu.Lock()
+	contextClusters["mgmt"] = &Cluster{dynamicClient: newFakeDynamicClient(
+		newObject("v1", "Pod", "flux-system", "source-controller", nil),
+	)}
+	contextClusters["workload"] = &Cluster{dynamicClient: newFakeDynamicClient(
+		newObject("v1", "Pod", "app", "web", nil),
+		newObject("v1", "Pod", "app", "worker", nil),
+	)}
+	contextClustersMu.Unlock()
+	t.Cleanup(func() {
+		contextClustersMu.Lock()
+		delete(contextClusters, "mgmt")
+		delete(contextClusters, "workload")
+		contextClustersMu.Unlock()
+	})
+
+	d, err := CreateKubernetesDomain(&KubernetesSpec{Resources: []Resource{
+		{Name: "pods", ResourceRule: &ResourceRule{Version: "v1", Resource: "pods"}, Contexts: []string{"mgmt", "workload"}},
+		{Name: "web", ResourceRule: &ResourceRule{Name: "web", Version: "v1", Resource: "pods", Namespaces: []string{"app"}}, Contexts: []string{"workload"}},
+	}})
+	require.NoError(t, err)
+
+	// the default cluster isn't needed, as every resource has contexts
+	drs, err := d.GetResources(context.Background())
+	require.NoError(t, err)
+
+	pods := drs["pods"].(map[string]interface{})
+	require.Len(t, pods["mgmt"], 1)
+	require.Len(t, pods["workload"], 2)
+	web := drs["web"].(map[string]interface{})["workload"].(map[string]interface{})
+	require.Equal(t, "web", web["metadata"].(map[string]interface{})["name"])
+}
+
+func TestCreateKubernetesDomainContexts(t *testing.T) {
+	rule := &ResourceRule{Version: "v1", Resource: "pods"}
+	tests := map[string]*KubernetesSpec{
+		"empty context":     {Resources: []Resource{{Name: "pods", ResourceRule: rule, Contexts: []string{""}}}},
+		"duplicate context": {Resources: []Resource{{Name: "pods", ResourceRule: rule, Contexts: []string{"a", "a"}}}},
+		"contexts with manifests": {
+			Manifests: []string{"manifests"},
+			Resources: []Resource{{Name: "pods", ResourceRule: rule, Contexts: []string{"a"}}},
+		},
+		"context with manifests": {
+			Manifests: []string{"manifests"},
+			Context:   "a",
+			Resources: []Resource{{Name: "pods", ResourceRule: rule}},
+		},
+	}
+
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CreateKubernetesDomain(spec)
+			require.Error(t, err)
+		})
+	}
+}
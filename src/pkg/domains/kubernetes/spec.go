@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
 	"github.com/defenseunicorns/lula/src/types"
 )
 
@@ -19,8 +22,8 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 		return nil, fmt.Errorf("spec is nil")
 	}
 
-	if spec.Resources == nil && spec.CreateResources == nil && spec.Wait == nil {
-		return nil, fmt.Errorf("one of resources, create-resources, or wait must be specified")
+	if spec.Resources == nil && spec.CreateResources == nil && spec.Wait == nil && spec.Waits == nil {
+		return nil, fmt.Errorf("one of resources, create-resources, wait or waits must be specified")
 	}
 
 	if spec.Resources != nil {
@@ -28,6 +31,12 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 			if resource.Name == "" {
 				return nil, fmt.Errorf("resource name cannot be empty")
 			}
+			if resource.Logs != nil || resource.Exec != nil || resource.AccessReview != nil {
+				if err := resource.validateRule(); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			if resource.ResourceRule == nil {
 				return nil, fmt.Errorf("resource rule cannot be nil")
 			}
@@ -40,6 +49,12 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 			if resource.ResourceRule.Name != "" && len(resource.ResourceRule.Namespaces) > 1 {
 				return nil, fmt.Errorf("named resource requested cannot be returned from multiple namespaces")
 			}
+			if err := resource.ResourceRule.validateSelectors(); err != nil {
+				return nil, err
+			}
+			if err := resource.ResourceRule.validateListing(); err != nil {
+				return nil, err
+			}
 			if resource.ResourceRule.Field != nil {
 				if resource.ResourceRule.Field.Type == "" {
 					resource.ResourceRule.Field.Type = DefaultFieldType
@@ -55,15 +70,48 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 		}
 	}
 
-	if spec.Wait != nil {
-		if spec.Wait.Resource == "" {
-			return nil, fmt.Errorf("wait resource cannot be empty")
+	for _, resource := range spec.Resources {
+		seen := make(map[string]bool, len(resource.Contexts))
+		for _, name := range resource.Contexts {
+			if name == "" {
+				return nil, fmt.Errorf("resource %s: context name cannot be empty", resource.Name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("resource %s: duplicate context %s", resource.Name, name)
+			}
+			seen[name] = true
 		}
-		if spec.Wait.Version == "" {
-			return nil, fmt.Errorf("wait version cannot be empty")
+	}
+
+	if len(spec.Manifests) > 0 {
+		if spec.CreateResources != nil || spec.Wait != nil || spec.Waits != nil {
+			return nil, fmt.Errorf("create-resources and waits cannot be specified with manifests")
+		}
+		if spec.Context != "" {
+			return nil, fmt.Errorf("context cannot be specified with manifests")
+		}
+		for _, resource := range spec.Resources {
+			if resource.Logs != nil || resource.Exec != nil || resource.AccessReview != nil {
+				return nil, fmt.Errorf("logs, exec and access-review cannot be specified with manifests")
+			}
+			if resource.ResourceRule.FieldSelector != "" {
+				return nil, fmt.Errorf("field-selector cannot be specified with manifests")
+			}
+			if len(resource.Contexts) > 0 {
+				return nil, fmt.Errorf("contexts cannot be specified with manifests")
+			}
+		}
+	}
+
+	if spec.Wait != nil {
+		if err := spec.Wait.validate(); err != nil {
+			return nil, err
 		}
-		if spec.Wait.Name == "" {
-			return nil, fmt.Errorf("wait name cannot be empty")
+	}
+
+	for _, w := range spec.Waits {
+		if err := w.validate(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -78,6 +126,9 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 			if resource.Manifest != "" && resource.File != "" {
 				return nil, fmt.Errorf("only resource manifest or file can be specified")
 			}
+			if resource.DryRun && resource.Namespace != "" {
+				return nil, fmt.Errorf("resource namespace cannot be specified with dry-run, as it would be created")
+			}
 		}
 	}
 
@@ -87,31 +138,38 @@ func CreateKubernetesDomain(spec *KubernetesSpec) (types.Domain, error) {
 }
 
 // GetResources returns the resources from the Kubernetes domain
-// Evaluates the `create-resources` first, `wait` second, and finally `resources` last
-func (k KubernetesDomain) GetResources(ctx context.Context) (types.DomainResources, error) {
+// Evaluates the `create-resources` first, `wait` and `waits` second, and finally `resources` last
+func (k KubernetesDomain) GetResources(ctx context.Context) (_ types.DomainResources, err error) {
 	createdResources := make(types.DomainResources)
 	resources := make(types.DomainResources)
 	var namespaces []string
 
-	cluster, err := GetCluster()
-	if err != nil {
-		return resources, err
+	// only connect to the default cluster if it's used, so resources in other
+	// contexts can be queried without access to it
+	var cluster *Cluster
+	if k.usesDefaultCluster() {
+		cluster, err = k.getCluster(ctx)
+		if err != nil {
+			return resources, err
+		}
 	}
 
 	// Evaluate the create-resources parameter
 	if k.Spec.CreateResources != nil {
 		createdResources, namespaces, err = CreateAllResources(ctx, cluster, k.Spec.CreateResources)
+		// Destroy the resources after everything else has been evaluated, or
+		// whatever was created if there was an error. The resources are
+		// deleted even if ctx is cancelled, without waiting for their removal.
+		if cluster != nil {
+			defer func() {
+				if cleanupErr := DestroyAllResources(ctx, cluster.kclient, createdOnly(createdResources, k.Spec.CreateResources), namespaces); cleanupErr != nil {
+					err = errors.Join(err, cleanupErr)
+				}
+			}()
+		}
 		if err != nil {
 			return resources, fmt.Errorf("error in create: %v", err)
 		}
-		// Destroy the resources after everything else has been evaluated
-		defer func() {
-			if cleanupErr := DestroyAllResources(ctx, cluster.kclient, createdResources, namespaces); cleanupErr != nil {
-				if err == nil {
-					err = cleanupErr
-				}
-			}
-		}()
 	}
 
 	// Evaluate the wait condition
@@ -121,10 +179,20 @@ func (k KubernetesDomain) GetResources(ctx context.Context) (types.DomainResourc
 			return resources, fmt.Errorf("error in wait: %v", err)
 		}
 	}
+	for _, w := range k.Spec.Waits {
+		if err := EvaluateWait(ctx, cluster, w); err != nil {
+			return resources, fmt.Errorf("error in wait: %v", err)
+		}
+	}
 
-	// Evaluate the resources parameter
+	// Evaluate the resources parameter. Resources read after create-resources
+	// or waits depend on what ran before, so they aren't cached for the run.
 	if k.Spec.Resources != nil {
-		resources, err = QueryCluster(ctx, cluster, k.Spec.Resources)
+		queryCtx := ctx
+		if k.Spec.CreateResources != nil || k.Spec.Wait != nil || k.Spec.Waits != nil {
+			queryCtx = types.WithResourceCache(ctx, nil)
+		}
+		resources, err = QueryCluster(queryCtx, cluster, k.Spec.Resources)
 		if err != nil {
 			return resources, fmt.Errorf("error in query: %v", err)
 		}
@@ -144,14 +212,71 @@ func (k KubernetesDomain) GetResources(ctx context.Context) (types.DomainResourc
 	return resources, nil
 }
 
+// getCluster returns the cluster to query, which is built from the manifests
+// if they are specified.
+func (k KubernetesDomain) getCluster(ctx context.Context) (*Cluster, error) {
+	if len(k.Spec.Manifests) == 0 {
+		return GetClusterForContext(k.Spec.Context)
+	}
+
+	workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
+	if !ok {
+		// if unset, assume lula is already working in the same directory the inputFile is in
+		workDir = "."
+	}
+
+	objects, err := loadManifests(k.Spec.Manifests, workDir)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*ResourceRule, 0, len(k.Spec.Resources))
+	for _, resource := range k.Spec.Resources {
+		if resource.ResourceRule != nil {
+			rules = append(rules, resource.ResourceRule)
+		}
+	}
+	return NewManifestCluster(objects, rules)
+}
+
+// usesDefaultCluster returns true unless every resource is queried in its own contexts
+func (k KubernetesDomain) usesDefaultCluster() bool {
+	if k.Spec.CreateResources != nil || k.Spec.Wait != nil || k.Spec.Waits != nil {
+		return true
+	}
+	for _, resource := range k.Spec.Resources {
+		if len(resource.Contexts) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (k KubernetesDomain) IsExecutable() bool {
-	// Domain is only executable if create-resources is not nil
-	return len(k.Spec.CreateResources) > 0
+	// Domain is only executable if create-resources creates resources (rather
+	// than a dry-run), or a command is exec'd in a pod
+	for _, resource := range k.Spec.CreateResources {
+		if !resource.DryRun {
+			return true
+		}
+	}
+	for _, resource := range k.Spec.Resources {
+		if resource.Exec != nil {
+			return true
+		}
+	}
+	return false
 }
 
 type KubernetesSpec struct {
-	Resources       []Resource       `json:"resources" yaml:"resources"`
-	Wait            *Wait            `json:"wait,omitempty" yaml:"wait,omitempty"`
+	// Manifests are files, directories, globs or URLs of manifests to query
+	// instead of a live cluster
+	Manifests []string `json:"manifests,omitempty" yaml:"manifests,omitempty"`
+	// Context is the kubeconfig context to use, defaults to the current context
+	Context   string     `json:"context,omitempty" yaml:"context,omitempty"`
+	Resources []Resource `json:"resources" yaml:"resources"`
+	Wait      *Wait      `json:"wait,omitempty" yaml:"wait,omitempty"`
+	// Waits are evaluated in order, after wait
+	Waits           []Wait           `json:"waits,omitempty" yaml:"waits,omitempty"`
 	CreateResources []CreateResource `json:"create-resources" yaml:"create-resources"`
 }
 
@@ -159,6 +284,15 @@ type Resource struct {
 	Name         string        `json:"name" yaml:"name"`
 	Description  string        `json:"description" yaml:"description"`
 	ResourceRule *ResourceRule `json:"resource-rule,omitempty" yaml:"resource-rule,omitempty"`
+	// Logs collects container logs instead of querying a resource rule
+	Logs *LogsRule `json:"logs,omitempty" yaml:"logs,omitempty"`
+	// Exec runs a command in a pod instead of querying a resource rule
+	Exec *ExecRule `json:"exec,omitempty" yaml:"exec,omitempty"`
+	// AccessReview reviews the access of subjects instead of querying a resource rule
+	AccessReview *AccessReviewRule `json:"access-review,omitempty" yaml:"access-review,omitempty"`
+	// Contexts are kubeconfig contexts to query the resource in, instead of the
+	// spec's context. The results are keyed by context name.
+	Contexts []string `json:"contexts,omitempty" yaml:"contexts,omitempty"`
 }
 
 type ResourceRule struct {
@@ -167,7 +301,78 @@ type ResourceRule struct {
 	Version    string   `json:"version" yaml:"version"`
 	Resource   string   `json:"resource" yaml:"resource"`
 	Namespaces []string `json:"namespaces" yaml:"namespaces"`
-	Field      *Field   `json:"field,omitempty" yaml:"field,omitempty"`
+	// LabelSelector filters the listed resources by label, e.g. "app=nginx,tier!=cache"
+	LabelSelector string `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	// FieldSelector filters the listed resources by field, e.g. "status.phase=Running"
+	FieldSelector string `json:"field-selector,omitempty" yaml:"field-selector,omitempty"`
+	// NamespaceLabelSelector limits the namespaces queried to those with matching labels
+	NamespaceLabelSelector string `json:"namespace-label-selector,omitempty" yaml:"namespace-label-selector,omitempty"`
+	Field                  *Field `json:"field,omitempty" yaml:"field,omitempty"`
+	// PageSize is the number of resources listed per request, defaults to 500
+	PageSize int64 `json:"page-size,omitempty" yaml:"page-size,omitempty"`
+	// MaxItems is the maximum number of resources returned, more is an error
+	MaxItems int `json:"max-items,omitempty" yaml:"max-items,omitempty"`
+	// KeepFields are the paths of the fields kept in each resource, e.g.
+	// .spec.containers[*].image, to reduce the size of large lists
+	KeepFields []string `json:"keep-fields,omitempty" yaml:"keep-fields,omitempty"`
+}
+
+// validateRule validates a resource that collects logs, execs in a pod or
+// reviews access, rather than querying a resource rule
+func (r Resource) validateRule() error {
+	rules := 0
+	for _, set := range []bool{r.ResourceRule != nil, r.Logs != nil, r.Exec != nil, r.AccessReview != nil} {
+		if set {
+			rules++
+		}
+	}
+	if rules != 1 {
+		return fmt.Errorf("resource %s: only one of resource-rule, logs, exec or access-review can be specified", r.Name)
+	}
+	switch {
+	case r.Logs != nil:
+		return r.Logs.validate()
+	case r.Exec != nil:
+		return r.Exec.validate()
+	default:
+		return r.AccessReview.validate()
+	}
+}
+
+// Validate the selectors of the ResourceRule
+func (r ResourceRule) validateSelectors() error {
+	if r.Name != "" && (r.LabelSelector != "" || r.FieldSelector != "" || r.NamespaceLabelSelector != "") {
+		return errors.New("selectors cannot be specified with resource name")
+	}
+	if _, err := labels.Parse(r.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label-selector: %w", err)
+	}
+	if _, err := fields.ParseSelector(r.FieldSelector); err != nil {
+		return fmt.Errorf("invalid field-selector: %w", err)
+	}
+	if _, err := labels.Parse(r.NamespaceLabelSelector); err != nil {
+		return fmt.Errorf("invalid namespace-label-selector: %w", err)
+	}
+	return nil
+}
+
+// Validate the list and pruning options of the ResourceRule
+func (r ResourceRule) validateListing() error {
+	if r.PageSize < 0 {
+		return errors.New("page-size cannot be negative")
+	}
+	if r.MaxItems < 0 {
+		return errors.New("max-items cannot be negative")
+	}
+	if len(r.KeepFields) > 0 && r.Field != nil {
+		return errors.New("keep-fields cannot be specified with field")
+	}
+	for _, path := range r.KeepFields {
+		if _, err := parseFieldPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type FieldType string
@@ -201,6 +406,24 @@ type Wait struct {
 	Resource  string `json:"resource" yaml:"resource"`
 	Namespace string `json:"namespace" yaml:"namespace"`
 	Timeout   string `json:"timeout" yaml:"timeout"`
+	// LabelSelector waits for every matching object instead of a named object
+	LabelSelector string `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	// Condition is what to wait for, defaults to the objects being ready
+	Condition *WaitCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+// WaitCondition is one of a status condition, a JSONPath value or deletion
+type WaitCondition struct {
+	// Type is a status condition type, e.g. Available
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Status is the status of the condition type, defaults to True
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	// JSONPath is a path in the object, e.g. {.status.phase}
+	JSONPath string `json:"jsonpath,omitempty" yaml:"jsonpath,omitempty"`
+	// Value is the value expected at the JSONPath
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Deleted waits for the objects to not exist
+	Deleted bool `json:"deleted,omitempty" yaml:"deleted,omitempty"`
 }
 
 type CreateResource struct {
@@ -208,4 +431,7 @@ type CreateResource struct {
 	Namespace string `json:"namespace" yaml:"namespace"`
 	Manifest  string `json:"manifest" yaml:"manifest"`
 	File      string `json:"file" yaml:"file"`
+	// DryRun submits the resources with server-side dry-run instead of creating
+	// them, returning the admission outcome of each
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
 }
@@ -1,22 +1,55 @@
 package kube
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
+const defaultWaitTimeout = "30s"
+
+// waitPollInterval is how often the objects are checked when waiting for a condition
+var waitPollInterval = time.Second
+
 func EvaluateWait(ctx context.Context, cluster *Cluster, waitPayload Wait) error {
 	if cluster == nil {
 		return fmt.Errorf("cluster is nil")
 	}
 
-	// TODO: incorporate wait for multiple objects?
+	// Set timeout
+	timeoutString := waitPayload.Timeout
+	if timeoutString == "" {
+		timeoutString = defaultWaitTimeout
+	}
+
+	// Timeout control parameters
+	duration, err := time.ParseDuration(timeoutString)
+	if err != nil {
+		return fmt.Errorf("invalid wait timeout: %s", timeoutString)
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, duration)
+	defer waitCancel()
+
+	// Objects selected by labels, or waiting for a condition, are polled
+	if waitPayload.LabelSelector != "" || waitPayload.Condition != nil {
+		return pollWait(waitCtx, cluster, waitPayload)
+	}
+
 	obj, err := cluster.validateAndGetGVR(waitPayload.Group, waitPayload.Version, waitPayload.Resource)
 	if err != nil {
 		return fmt.Errorf("unable to validate GVR: %v", err)
@@ -30,19 +63,217 @@ func EvaluateWait(ctx context.Context, cluster *Cluster, waitPayload Wait) error
 		},
 	}
 
-	// Set timeout
-	timeoutString := waitPayload.Timeout
-	if timeoutString == "" {
-		timeoutString = "30s"
+	message.Debugf("Waiting for %s %s/%s to be ready", waitPayload.Resource, waitPayload.Name, waitPayload.Namespace)
+	return pkgkubernetes.WaitForReady(waitCtx, cluster.watcher, []object.ObjMetadata{objMeta})
+}
+
+// validate checks the wait is well-formed
+func (w Wait) validate() error {
+	if w.Resource == "" {
+		return fmt.Errorf("wait resource cannot be empty")
+	}
+	if w.Version == "" {
+		return fmt.Errorf("wait version cannot be empty")
+	}
+	if w.Name == "" && w.LabelSelector == "" {
+		return fmt.Errorf("wait name cannot be empty")
+	}
+	if w.Name != "" && w.LabelSelector != "" {
+		return fmt.Errorf("wait name and label-selector cannot both be specified")
 	}
+	if _, err := labels.Parse(w.LabelSelector); err != nil {
+		return fmt.Errorf("invalid wait label-selector: %w", err)
+	}
+	if w.Timeout != "" {
+		if _, err := time.ParseDuration(w.Timeout); err != nil {
+			return fmt.Errorf("invalid wait timeout: %s", w.Timeout)
+		}
+	}
+	if w.Condition != nil {
+		return w.Condition.validate()
+	}
+	return nil
+}
 
-	// Timeout control parameters
-	duration, err := time.ParseDuration(timeoutString)
+func (c WaitCondition) validate() error {
+	kinds := 0
+	if c.Type != "" {
+		kinds++
+	}
+	if c.JSONPath != "" {
+		kinds++
+		if _, err := parseJSONPath(c.JSONPath); err != nil {
+			return fmt.Errorf("invalid wait condition jsonpath: %w", err)
+		}
+	}
+	if c.Deleted {
+		kinds++
+	}
+	if kinds != 1 {
+		return errors.New("wait condition must specify exactly one of type, jsonpath or deleted")
+	}
+	if c.Status != "" && c.Type == "" {
+		return errors.New("wait condition status requires a type")
+	}
+	if c.Value != "" && c.JSONPath == "" {
+		return errors.New("wait condition value requires a jsonpath")
+	}
+	return nil
+}
+
+// pollWait polls the objects until they all meet the condition, or ctx is done
+func pollWait(ctx context.Context, cluster *Cluster, w Wait) error {
+	gvr := schema.GroupVersionResource{Group: w.Group, Version: w.Version, Resource: w.Resource}
+	target := w.Name
+	if target == "" {
+		target = w.LabelSelector
+	}
+	message.Debugf("Waiting for %s %s/%s to be %s", w.Resource, w.Namespace, target, w.Condition.describe())
+
+	var lastErr error
+	err := wait.PollUntilContextCancel(ctx, waitPollInterval, true, func(ctx context.Context) (bool, error) {
+		objects, err := waitObjects(ctx, cluster, gvr, w)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		lastErr = checkCondition(objects, w.Condition)
+		return lastErr == nil, nil
+	})
 	if err != nil {
-		return fmt.Errorf("invalid wait timeout: %s", timeoutString)
+		return fmt.Errorf("timed out waiting for %s %s/%s to be %s: %w", w.Resource, w.Namespace, target, w.Condition.describe(), lastErr)
+	}
+	return nil
+}
+
+// waitObjects returns the objects targeted by the wait, which is empty if none exist
+func waitObjects(ctx context.Context, cluster *Cluster, gvr schema.GroupVersionResource, w Wait) ([]unstructured.Unstructured, error) {
+	client := cluster.dynamicClient.Resource(gvr).Namespace(w.Namespace)
+	if w.Name != "" {
+		obj, err := client.Get(ctx, w.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []unstructured.Unstructured{*obj}, nil
+	}
+
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: w.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// checkCondition returns nil if every object meets the condition, or an error
+// describing an object that doesn't. A nil condition waits for the objects to
+// be ready.
+func checkCondition(objects []unstructured.Unstructured, c *WaitCondition) error {
+	if c != nil && c.Deleted {
+		if len(objects) > 0 {
+			return fmt.Errorf("%s still exists", objectKey(&objects[0]))
+		}
+		return nil
+	}
+	if len(objects) == 0 {
+		return errors.New("no objects found")
+	}
+
+	for i := range objects {
+		obj := &objects[i]
+		var err error
+		switch {
+		case c == nil:
+			err = checkReady(obj)
+		case c.Type != "":
+			err = checkStatusCondition(obj, c.Type, c.Status)
+		default:
+			err = checkJSONPath(obj, c.JSONPath, c.Value)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", objectKey(obj), err)
+		}
+	}
+	return nil
+}
+
+func checkReady(obj *unstructured.Unstructured) error {
+	result, err := status.Compute(obj)
+	if err != nil {
+		return err
+	}
+	if result.Status != status.CurrentStatus {
+		return fmt.Errorf("status is %s: %s", result.Status, result.Message)
+	}
+	return nil
+}
+
+// checkStatusCondition checks the object has a status condition of the type
+// with the status, which defaults to True. Both are compared case-insensitively.
+func checkStatusCondition(obj *unstructured.Unstructured, conditionType, conditionStatus string) error {
+	if conditionStatus == "" {
+		conditionStatus = "True"
+	}
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || !strings.EqualFold(fmt.Sprint(condition["type"]), conditionType) {
+			continue
+		}
+		if strings.EqualFold(fmt.Sprint(condition["status"]), conditionStatus) {
+			return nil
+		}
+		return fmt.Errorf("condition %s is %v", conditionType, condition["status"])
+	}
+	return fmt.Errorf("condition %s not found", conditionType)
+}
+
+// checkJSONPath checks the value at the JSONPath equals value
+func checkJSONPath(obj *unstructured.Unstructured, path, value string) error {
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, obj.Object); err != nil {
+		return err
+	}
+	if buf.String() != value {
+		return fmt.Errorf("%s is %q", path, buf.String())
+	}
+	return nil
+}
+
+// parseJSONPath parses a kubectl-style JSONPath, with or without the enclosing braces
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+func (c *WaitCondition) describe() string {
+	switch {
+	case c == nil:
+		return "ready"
+	case c.Deleted:
+		return "deleted"
+	case c.Type != "":
+		s := c.Status
+		if s == "" {
+			s = "True"
+		}
+		return fmt.Sprintf("%s=%s", c.Type, s)
+	default:
+		return fmt.Sprintf("%s=%s", c.JSONPath, c.Value)
 	}
-	waitCtx, waitCancel := context.WithTimeout(ctx, duration)
-	defer waitCancel()
-	message.Debugf("Waiting for %s %s/%s to be ready", waitPayload.Resource, waitPayload.Name, waitPayload.Namespace)
-	return pkgkubernetes.WaitForReady(waitCtx, cluster.watcher, []object.ObjMetadata{objMeta})
 }
@@ -0,0 +1,161 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestEvaluateWaitConditions(t *testing.T) {
+	waitPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { waitPollInterval = time.Second })
+
+	ready := newPod("app", "web-1", "Running", "Ready", "True")
+	notReady := newPod("app", "web-2", "Pending", "Ready", "False")
+	other := newPod("system", "web-1", "Running", "Ready", "True")
+
+	tests := map[string]struct {
+		wait    Wait
+		wantErr bool
+	}{
+		"condition type on selected objects": {
+			wait: Wait{Version: "v1", Resource: "pods", Namespace: "app", LabelSelector: "app=web", Condition: &WaitCondition{Type: "ready"}},
+			// web-2 is not ready
+			wantErr: true,
+		},
+		"condition status on selected objects": {
+			wait: Wait{Version: "v1", Resource: "pods", Namespace: "system", LabelSelector: "app=web", Condition: &WaitCondition{Type: "Ready", Status: "true"}},
+		},
+		"jsonpath on named object": {
+			wait: Wait{Version: "v1", Resource: "pods", Namespace: "app", Name: "web-1", Condition: &WaitCondition{JSONPath: ".status.phase", Value: "Running"}},
+		},
+		"jsonpath with braces": {
+			wait: Wait{Version: "v1", Resource: "pods", Namespace: "app", Name: "web-2", Condition: &WaitCondition{JSONPath: "{.status.phase}", Value: "Pending"}},
+		},
+		"jsonpath mismatch": {
+			wait:    Wait{Version: "v1", Resource: "pods", Namespace: "app", Name: "web-2", Condition: &WaitCondition{JSONPath: ".status.phase", Value: "Running"}},
+			wantErr: true,
+		},
+		"deleted named object": {
+			wait: Wait{Version: "v1", Resource: "pods", Namespace: "app", Name: "missing", Condition: &WaitCondition{Deleted: true}},
+		},
+		"deleted selected objects": {
+			wait: Wait{Version: "v1", Resource: "pods", LabelSelector: "app=missing", Condition: &WaitCondition{Deleted: true}},
+		},
+		"not deleted": {
+			wait:    Wait{Version: "v1", Resource: "pods", Namespace: "app", Name: "web-1", Condition: &WaitCondition{Deleted: true}},
+			wantErr: true,
+		},
+		"no selected objects": {
+			wait:    Wait{Version: "v1", Resource: "pods", LabelSelector: "app=missing", Condition: &WaitCondition{Type: "Ready"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster := &Cluster{dynamicClient: newFakeDynamicClient(ready, notReady, other)}
+			tt.wait.Timeout = "100ms"
+			err := EvaluateWait(context.Background(), cluster, tt.wait)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateWait() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateWaitDeletion(t *testing.T) {
+	waitPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { waitPollInterval = time.Second })
+
+	client := newFakeDynamicClient(newPod("app", "web", "Running", "Ready", "True"))
+	cluster := &Cluster{dynamicClient: client}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace("app").Delete(context.Background(), "web", metav1.DeleteOptions{})
+	}()
+
+	err := EvaluateWait(context.Background(), cluster, Wait{
+		Version:       "v1",
+		Resource:      "pods",
+		Namespace:     "app",
+		LabelSelector: "app=web",
+		Timeout:       "5s",
+		Condition:     &WaitCondition{Deleted: true},
+	})
+	require.NoError(t, err)
+}
+
+func TestWaitValidate(t *testing.T) {
+	tests := map[string]struct {
+		wait    Wait
+		wantErr string
+	}{
+		"named": {
+			wait: Wait{Version: "v1", Resource: "pods", Name: "web"},
+		},
+		"selector with condition": {
+			wait: Wait{Version: "v1", Resource: "pods", LabelSelector: "app=web", Condition: &WaitCondition{Type: "Ready", Status: "False"}},
+		},
+		"missing name and selector": {
+			wait:    Wait{Version: "v1", Resource: "pods"},
+			wantErr: "wait name cannot be empty",
+		},
+		"name and selector": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", LabelSelector: "app=web"},
+			wantErr: "cannot both be specified",
+		},
+		"invalid selector": {
+			wait:    Wait{Version: "v1", Resource: "pods", LabelSelector: "app in ("},
+			wantErr: "invalid wait label-selector",
+		},
+		"invalid timeout": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", Timeout: "soon"},
+			wantErr: "invalid wait timeout",
+		},
+		"multiple conditions": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", Condition: &WaitCondition{Type: "Ready", Deleted: true}},
+			wantErr: "exactly one of",
+		},
+		"empty condition": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", Condition: &WaitCondition{}},
+			wantErr: "exactly one of",
+		},
+		"value without jsonpath": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", Condition: &WaitCondition{Type: "Ready", Value: "Running"}},
+			wantErr: "value requires a jsonpath",
+		},
+		"invalid jsonpath": {
+			wait:    Wait{Version: "v1", Resource: "pods", Name: "web", Condition: &WaitCondition{JSONPath: "{.status[", Value: "Running"}},
+			wantErr: "invalid wait condition jsonpath",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.wait.validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func newPod(namespace, name, phase, conditionType, conditionStatus string) *unstructured.Unstructured {
+	pod := newObject("v1", "Pod", namespace, name, map[string]string{"app": "web"})
+	pod.Object["status"] = map[string]interface{}{
+		"phase": phase,
+		"conditions": []interface{}{
+			map[string]interface{}{"type": conditionType, "status": conditionStatus},
+		},
+	}
+	return pod
+}
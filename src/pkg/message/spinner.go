@@ -6,16 +6,21 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/pterm/pterm"
 )
 
-var activeSpinner *Spinner
+var (
+	activeSpinner   *Spinner
+	activeSpinnerMu sync.Mutex
+)
 
 var sequence = []string{`  ⠋ `, `  ⠙ `, `  ⠹ `, `  ⠸ `, `  ⠼ `, `  ⠴ `, `  ⠦ `, `  ⠧ `, `  ⠇ `, `  ⠏ `}
 
-// Spinner is a wrapper around pterm.SpinnerPrinter.
+// Spinner is a wrapper around pterm.SpinnerPrinter. It is safe for concurrent use.
 type Spinner struct {
+	mu             sync.Mutex
 	spinner        *pterm.SpinnerPrinter
 	startText      string
 	termWidth      int
@@ -24,6 +29,8 @@ type Spinner struct {
 
 // NewProgressSpinner creates a new progress spinner.
 func NewProgressSpinner(format string, a ...any) *Spinner {
+	activeSpinnerMu.Lock()
+	defer activeSpinnerMu.Unlock()
 	if activeSpinner != nil {
 		activeSpinner.Updatef(format, a...)
 		debugPrinter(2, "Active spinner already exists")
@@ -53,16 +60,22 @@ func NewProgressSpinner(format string, a ...any) *Spinner {
 
 // EnablePreserveWrites enables preserving writes to the terminal.
 func (p *Spinner) EnablePreserveWrites() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.preserveWrites = true
 }
 
 // DisablePreserveWrites disables preserving writes to the terminal.
 func (p *Spinner) DisablePreserveWrites() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.preserveWrites = false
 }
 
 // Write the given text to the spinner.
 func (p *Spinner) Write(raw []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	size := len(raw)
 	if NoProgress {
 		if p.preserveWrites {
@@ -92,6 +105,8 @@ func (p *Spinner) Write(raw []byte) (int, error) {
 
 // Updatef updates the spinner text.
 func (p *Spinner) Updatef(format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if NoProgress {
 		debugPrinter(2, fmt.Sprintf(format, a...))
 		return
@@ -104,11 +119,16 @@ func (p *Spinner) Updatef(format string, a ...any) {
 
 // Stop the spinner.
 func (p *Spinner) Stop() {
+	p.mu.Lock()
 	if p.spinner != nil && p.spinner.IsActive {
 		//nolint:errcheck
 		p.spinner.Stop() // #nosec G104
 	}
+	p.mu.Unlock()
+
+	activeSpinnerMu.Lock()
 	activeSpinner = nil
+	activeSpinnerMu.Unlock()
 }
 
 // Success prints a success message and stops the spinner.
@@ -119,17 +139,21 @@ func (p *Spinner) Success() {
 // Successf prints a success message with the spinner and stops it.
 func (p *Spinner) Successf(format string, a ...any) {
 	text := pterm.Sprintf(format, a...)
+	p.mu.Lock()
 	if p.spinner != nil {
 		p.spinner.Success(text)
 	} else {
 		Info(text)
 	}
+	p.mu.Unlock()
 	p.Stop()
 }
 
 // Warnf prints a warning message with the spinner.
 func (p *Spinner) Warnf(format string, a ...any) {
 	text := pterm.Sprintf(format, a...)
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if p.spinner != nil {
 		p.spinner.Warning(text)
 	} else {
@@ -150,17 +174,26 @@ func (p *Spinner) Fatal(err error) {
 
 // Fatalf calls message.Fatalf with the given error and format.
 func (p *Spinner) Fatalf(err error, format string, a ...any) {
-	if p.spinner != nil {
+	p.mu.Lock()
+	stopped := p.spinner != nil
+	if stopped {
 		p.spinner.RemoveWhenDone = true
 		//nolint:errcheck
 		p.spinner.Stop() // #nosec G104
+	}
+	p.mu.Unlock()
+	if stopped {
+		activeSpinnerMu.Lock()
 		activeSpinner = nil
+		activeSpinnerMu.Unlock()
 	}
 	Fatalf(err, format, a...)
 }
 
 // Pause the spinner.
 func (p *Spinner) Pause() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	var spinnerText string
 	if p.spinner != nil && p.spinner.IsActive {
 		spinnerText = p.spinner.Text
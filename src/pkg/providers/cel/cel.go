@@ -0,0 +1,132 @@
+package cel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/defenseunicorns/lula/src/pkg/message"
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+// resourcesVariable is the name of the variable the domain resources are bound to in expressions
+const resourcesVariable = "resources"
+
+// newEnvironment creates the CEL environment shared by all expressions of a provider
+func newEnvironment() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(resourcesVariable, cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+		ext.Lists(),
+		ext.Sets(),
+		ext.Encoders(),
+	)
+}
+
+// compileExpression parses and checks the expression, returning a program that can be evaluated
+// repeatedly. If requireBool is set, the expression must be able to evaluate to a boolean.
+func compileExpression(env *cel.Env, expression string, requireBool bool) (cel.Program, error) {
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCompileExpression, iss.Err())
+	}
+
+	if requireBool {
+		outputType := ast.OutputType()
+		if !outputType.IsExactType(cel.BoolType) && !outputType.IsExactType(cel.DynType) {
+			return nil, fmt.Errorf("%w: got %s", ErrInvalidValidationType, outputType)
+		}
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCompileExpression, err)
+	}
+
+	return program, nil
+}
+
+// GetValidatedAssets evaluates the compiled validation and observation programs against the resources,
+// the result is not-applicable if the optional notApplicable program evaluates to true
+func GetValidatedAssets(ctx context.Context, validation cel.Program, notApplicable cel.Program, observations []compiledObservation, resources map[string]interface{}) (types.Result, error) {
+	var matchResult types.Result
+
+	if len(resources) == 0 {
+		return matchResult, ErrNoResourcesToEvaluate
+	}
+
+	activation := map[string]interface{}{
+		resourcesVariable: resources,
+	}
+
+	// A validation that does not apply is neither passing nor failing
+	if notApplicable != nil {
+		out, _, err := notApplicable.ContextEval(ctx, activation)
+		if err != nil {
+			return matchResult, fmt.Errorf("%w: not-applicable: %w", ErrEvaluateExpression, err)
+		}
+		if na, ok := out.Value().(bool); ok && na {
+			matchResult.State = "not-applicable"
+			return matchResult, nil
+		}
+	}
+
+	out, _, err := validation.ContextEval(ctx, activation)
+	if err != nil {
+		return matchResult, fmt.Errorf("%w: %w", ErrEvaluateExpression, err)
+	}
+
+	// Extra check on validation value = true, to ensure it's a boolean return since dyn could be anything
+	if matched, ok := out.Value().(bool); ok && matched {
+		matchResult.Passing += 1
+	} else {
+		matchResult.Failing += 1
+		if !ok {
+			message.Debugf("Validation expression expected bool and got %s", reflect.TypeOf(out.Value()))
+		}
+	}
+
+	// Get additional observations, if they exist
+	obs := make(map[string]interface{})
+	for _, obv := range observations {
+		out, _, err := obv.program.ContextEval(ctx, activation)
+		if err != nil {
+			return matchResult, fmt.Errorf("%w: observation %s: %w", ErrEvaluateExpression, obv.name, err)
+		}
+
+		value, err := observationValue(out)
+		if err != nil {
+			message.Debugf("Observation %s: %v", obv.name, err)
+			continue
+		}
+		obs[obv.name] = value
+	}
+
+	matchResult.Observations = obs
+
+	return matchResult, nil
+}
+
+// observationValue converts the result of an observation expression to its native JSON form,
+// strings are used as-is while any other value is kept as structured data
+func observationValue(val ref.Val) (interface{}, error) {
+	if s, ok := val.Value().(string); ok {
+		return s, nil
+	}
+
+	native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrObservationUnsupported, err)
+	}
+	pbValue, ok := native.(*structpb.Value)
+	if !ok {
+		return nil, ErrObservationUnsupported
+	}
+
+	return pbValue.AsInterface(), nil
+}
@@ -0,0 +1,148 @@
+package cel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/pkg/providers/cel"
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+func TestCelEvaluate(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             *cel.CelSpec
+		resources        types.DomainResources
+		wantErr          error
+		wantPassing      int
+		wantFailing      int
+		wantState        string
+		wantObservations map[string]interface{}
+	}{
+		{
+			name: "passing validation",
+			spec: &cel.CelSpec{
+				Validation: "resources.pods.all(p, p.metadata.labels.lula == 'true')",
+			},
+			resources:        dummyPods,
+			wantPassing:      1,
+			wantObservations: map[string]interface{}{},
+		},
+		{
+			name: "failing validation",
+			spec: &cel.CelSpec{
+				Validation: "resources.pods.exists(p, p.metadata.name == 'missing')",
+			},
+			resources:        dummyPods,
+			wantFailing:      1,
+			wantObservations: map[string]interface{}{},
+		},
+		{
+			name: "non-boolean dynamic validation fails",
+			spec: &cel.CelSpec{
+				Validation: "resources.pods[0].metadata.name",
+			},
+			resources:        dummyPods,
+			wantFailing:      1,
+			wantObservations: map[string]interface{}{},
+		},
+		{
+			name: "observations",
+			spec: &cel.CelSpec{
+				Validation: "size(resources.pods) == 2",
+				Observations: []cel.CelObservation{
+					{Name: "first-pod", Expression: "resources.pods[0].metadata.name"},
+					{Name: "pod-count", Expression: "size(resources.pods)"},
+					{Name: "names", Expression: "resources.pods.map(p, p.metadata.name)"},
+				},
+			},
+			resources:   dummyPods,
+			wantPassing: 1,
+			wantObservations: map[string]interface{}{
+				"first-pod": "pod-a",
+				"pod-count": float64(2),
+				"names":     []interface{}{"pod-a", "pod-b"},
+			},
+		},
+		{
+			name: "not applicable",
+			spec: &cel.CelSpec{
+				Validation:    "resources.pods.all(p, p.metadata.labels.lula == 'true')",
+				NotApplicable: "!resources.pods.exists(p, p.metadata.name == 'pod-c')",
+			},
+			resources: dummyPods,
+			wantState: "not-applicable",
+		},
+		{
+			name: "applicable",
+			spec: &cel.CelSpec{
+				Validation:    "resources.pods.all(p, p.metadata.labels.lula == 'true')",
+				NotApplicable: "size(resources.pods) == 0",
+			},
+			resources:        dummyPods,
+			wantPassing:      1,
+			wantObservations: map[string]interface{}{},
+		},
+		{
+			name: "no resources",
+			spec: &cel.CelSpec{
+				Validation: "true",
+			},
+			resources: types.DomainResources{},
+			wantErr:   cel.ErrNoResourcesToEvaluate,
+		},
+		{
+			name: "evaluation error",
+			spec: &cel.CelSpec{
+				Validation: "resources.missing.size() > 0",
+			},
+			resources: dummyPods,
+			wantErr:   cel.ErrEvaluateExpression,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, err := cel.CreateCelProvider(ctx, tt.spec)
+			require.NoError(t, err)
+
+			result, err := provider.Evaluate(ctx, tt.resources)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			require.Equal(t, tt.wantPassing, result.Passing)
+			require.Equal(t, tt.wantFailing, result.Failing)
+			require.Equal(t, tt.wantState, result.State)
+			require.Equal(t, tt.wantObservations, result.Observations)
+		})
+	}
+}
+
+var dummyPods = types.DomainResources{
+	"pods": []interface{}{
+		map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "pod-a",
+				"labels": map[string]string{
+					"lula": "true",
+				},
+			},
+		},
+		map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "pod-b",
+				"labels": map[string]string{
+					"lula": "true",
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,133 @@
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+var (
+	ErrNilSpec                = errors.New("spec is nil")
+	ErrEmptyValidation        = errors.New("validation expression cannot be empty")
+	ErrEmptyObservationName   = errors.New("observation name cannot be empty")
+	ErrDuplicateObservation   = errors.New("observation name must be unique")
+	ErrCreateEnvironment      = errors.New("failed to create cel environment")
+	ErrCompileExpression      = errors.New("failed to compile cel expression")
+	ErrInvalidValidationType  = errors.New("validation expression must evaluate to a boolean")
+	ErrEvaluateExpression     = errors.New("failed to evaluate cel expression")
+	ErrNoResourcesToEvaluate  = errors.New("cel validation not performed - no resources to validate")
+	ErrObservationUnsupported = errors.New("observation value could not be converted to a string")
+)
+
+type CelProvider struct {
+	// Spec is the specification of the CEL expressions
+	Spec *CelSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+
+	// validation is the compiled validation expression
+	validation cel.Program
+
+	// notApplicable is the compiled not-applicable expression, nil if unset
+	notApplicable cel.Program
+
+	// observations are the compiled observation expressions, in the order they were specified
+	observations []compiledObservation
+}
+
+type compiledObservation struct {
+	name    string
+	program cel.Program
+}
+
+// CreateCelProvider validates the spec and compiles every expression once, so that
+// Evaluate only has to run the resulting programs against the domain resources.
+func CreateCelProvider(_ context.Context, spec *CelSpec) (types.Provider, error) {
+	// Check validity of spec
+	if spec == nil {
+		return nil, ErrNilSpec
+	}
+	if spec.Validation == "" {
+		return nil, ErrEmptyValidation
+	}
+
+	env, err := newEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateEnvironment, err)
+	}
+
+	validation, err := compileExpression(env, spec.Validation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var notApplicable cel.Program
+	if spec.NotApplicable != "" {
+		notApplicable, err = compileExpression(env, spec.NotApplicable, true)
+		if err != nil {
+			return nil, fmt.Errorf("not-applicable: %w", err)
+		}
+	}
+
+	observations := make([]compiledObservation, 0, len(spec.Observations))
+	seen := make(map[string]bool, len(spec.Observations))
+	for _, obv := range spec.Observations {
+		if obv.Name == "" {
+			return nil, ErrEmptyObservationName
+		}
+		if seen[obv.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateObservation, obv.Name)
+		}
+		seen[obv.Name] = true
+
+		program, err := compileExpression(env, obv.Expression, false)
+		if err != nil {
+			return nil, fmt.Errorf("observation %s: %w", obv.Name, err)
+		}
+		observations = append(observations, compiledObservation{name: obv.Name, program: program})
+	}
+
+	return CelProvider{
+		Spec:          spec,
+		validation:    validation,
+		notApplicable: notApplicable,
+		observations:  observations,
+	}, nil
+}
+
+func (c CelProvider) Evaluate(ctx context.Context, resources types.DomainResources) (types.Result, error) {
+	results, err := GetValidatedAssets(ctx, c.validation, c.notApplicable, c.observations, resources)
+	if err != nil {
+		return types.Result{}, err
+	}
+
+	return results, nil
+}
+
+// IsExecutable returns false
+func (c CelProvider) IsExecutable() bool {
+	return false
+}
+
+// CelSpec is the specification of the CEL expressions, required if the provider type is cel
+type CelSpec struct {
+	// Required: Validation is a CEL expression that must evaluate to a boolean. The domain
+	// resources are available to the expression as the `resources` variable.
+	Validation string `json:"validation" yaml:"validation"`
+	// Optional: NotApplicable is a CEL expression that marks the validation as not applicable when it
+	// evaluates to true, e.g., when no matching resources exist.
+	NotApplicable string `json:"not-applicable,omitempty" yaml:"not-applicable,omitempty"`
+	// Optional: Observations are named CEL expressions whose results are added to the
+	// observations of the validation result.
+	Observations []CelObservation `json:"observations,omitempty" yaml:"observations,omitempty"`
+}
+
+// CelObservation is a named CEL expression evaluated against the domain resources
+type CelObservation struct {
+	// Required: Name is the key of the observation in the result
+	Name string `json:"name" yaml:"name"`
+	// Required: Expression is the CEL expression to evaluate
+	Expression string `json:"expression" yaml:"expression"`
+}
@@ -0,0 +1,103 @@
+package cel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/defenseunicorns/lula/src/pkg/providers/cel"
+)
+
+func TestCreateCelProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    *cel.CelSpec
+		wantErr error
+	}{
+		{
+			name: "valid spec",
+			spec: &cel.CelSpec{
+				Validation: "size(resources) > 0",
+			},
+		},
+		{
+			name: "valid spec with observations",
+			spec: &cel.CelSpec{
+				Validation: "size(resources) > 0",
+				Observations: []cel.CelObservation{
+					{Name: "count", Expression: "string(size(resources))"},
+				},
+			},
+		},
+		{
+			name:    "nil spec",
+			spec:    nil,
+			wantErr: cel.ErrNilSpec,
+		},
+		{
+			name:    "empty validation",
+			spec:    &cel.CelSpec{},
+			wantErr: cel.ErrEmptyValidation,
+		},
+		{
+			name: "invalid expression",
+			spec: &cel.CelSpec{
+				Validation: "resources.pod.(",
+			},
+			wantErr: cel.ErrCompileExpression,
+		},
+		{
+			name: "non-boolean validation",
+			spec: &cel.CelSpec{
+				Validation: "size(resources)",
+			},
+			wantErr: cel.ErrInvalidValidationType,
+		},
+		{
+			name: "non-boolean not-applicable",
+			spec: &cel.CelSpec{
+				Validation:    "true",
+				NotApplicable: "size(resources)",
+			},
+			wantErr: cel.ErrInvalidValidationType,
+		},
+		{
+			name: "empty observation name",
+			spec: &cel.CelSpec{
+				Validation:   "true",
+				Observations: []cel.CelObservation{{Expression: "'foo'"}},
+			},
+			wantErr: cel.ErrEmptyObservationName,
+		},
+		{
+			name: "duplicate observation name",
+			spec: &cel.CelSpec{
+				Validation: "true",
+				Observations: []cel.CelObservation{
+					{Name: "foo", Expression: "'foo'"},
+					{Name: "foo", Expression: "'bar'"},
+				},
+			},
+			wantErr: cel.ErrDuplicateObservation,
+		},
+		{
+			name: "invalid observation expression",
+			spec: &cel.CelSpec{
+				Validation:   "true",
+				Observations: []cel.CelObservation{{Name: "foo", Expression: "unknown_fn()"}},
+			},
+			wantErr: cel.ErrCompileExpression,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cel.CreateCelProvider(context.Background(), tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateCelProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
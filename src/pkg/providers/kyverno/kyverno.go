@@ -3,11 +3,15 @@ package kyverno
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	"github.com/defenseunicorns/lula/src/types"
 	kjson "github.com/kyverno/kyverno-json/pkg/apis/policy/v1alpha1"
+	"github.com/kyverno/kyverno-json/pkg/matching"
 
 	jsonengine "github.com/kyverno/kyverno-json/pkg/json-engine"
 )
@@ -16,7 +20,7 @@ func GetValidatedAssets(ctx context.Context, kyvernoPolicies *kjson.ValidatingPo
 	var matchResult types.Result
 
 	if len(resources) == 0 {
-		matchResult.Observations = map[string]string{"Kyverno validation not performed": "No resources to validate"}
+		matchResult.Observations = map[string]interface{}{"Kyverno validation not performed": "No resources to validate"}
 		return matchResult, nil
 	}
 
@@ -30,41 +34,45 @@ func GetValidatedAssets(ctx context.Context, kyvernoPolicies *kjson.ValidatingPo
 
 	validationSet := make(map[string]map[string]bool)
 	if output.Validation != "" {
-		validationPairs := strings.Split(output.Validation, ",")
-
-		for _, pair := range validationPairs {
-			pair := strings.Split(pair, ".")
-
-			if len(pair) != 2 {
+		for _, pair := range strings.Split(output.Validation, ",") {
+			if policyName, ruleName, ok := parseRulePair(pair); ok {
+				addRulePair(validationSet, policyName, ruleName)
+			} else {
 				message.Debugf("Invalid validation pair: %v", pair)
-				continue
-			}
-
-			validationPolicy := strings.TrimSpace(pair[0])
-			validationRule := strings.TrimSpace(pair[1])
-			if _, ok := validationSet[validationPolicy]; !ok {
-				validationSet[validationPolicy] = make(map[string]bool)
 			}
-			validationSet[validationPolicy][validationRule] = true
 		}
 	}
 
 	observationSet := make(map[string]map[string]bool)
-	if len(output.Observations) > 0 {
-		for _, observationPair := range output.Observations {
-			pair := strings.Split(observationPair, ".")
-
-			if len(pair) != 2 {
-				message.Debugf("Invalid validation pair: %v", pair)
-				continue
-			}
+	for _, pair := range output.Observations {
+		if policyName, ruleName, ok := parseRulePair(pair); ok {
+			addRulePair(observationSet, policyName, ruleName)
+		} else {
+			message.Debugf("Invalid observation pair: %v", pair)
+		}
+	}
 
-			observationPolicy := strings.TrimSpace(pair[0])
-			observationRule := strings.TrimSpace(pair[1])
-			if _, ok := observationSet[observationPolicy]; !ok {
-				observationSet[observationPolicy] = make(map[string]bool)
+	// Rule outputs name the observation of a rule and select or exclude it from the validation,
+	// selected rules only add to the rules of output.validation
+	ruleOutputs := make(map[string]map[string]KyvernoRuleOutput)
+	selectedSet := make(map[string]map[string]bool)
+	excludedSet := make(map[string]map[string]bool)
+	for _, ruleOutput := range output.Rules {
+		policyName, ruleName, ok := parseRulePair(ruleOutput.Rule)
+		if !ok {
+			message.Debugf("Invalid rule pair: %v", ruleOutput.Rule)
+			continue
+		}
+		if _, ok := ruleOutputs[policyName]; !ok {
+			ruleOutputs[policyName] = make(map[string]KyvernoRuleOutput)
+		}
+		ruleOutputs[policyName][ruleName] = ruleOutput
+		if ruleOutput.Validation != nil {
+			if *ruleOutput.Validation {
+				addRulePair(selectedSet, policyName, ruleName)
+			} else {
+				addRulePair(excludedSet, policyName, ruleName)
 			}
-			observationSet[observationPolicy][observationRule] = true
 		}
 	}
 
@@ -77,7 +85,8 @@ func GetValidatedAssets(ctx context.Context, kyvernoPolicies *kjson.ValidatingPo
 		Policies: policyarr,
 	})
 
-	observations := make(map[string]string)
+	observations := make(map[string]interface{})
+	var resourceResults []types.ResourceResult
 	for i, policy := range response.Policies {
 		for j, rule := range policy.Rules {
 			if rule.Error != nil {
@@ -85,17 +94,33 @@ func GetValidatedAssets(ctx context.Context, kyvernoPolicies *kjson.ValidatingPo
 				continue
 			}
 
-			if _, ok := validationSet[policy.Policy.Name][rule.Rule.Name]; output.Validation == "" || ok {
+			selected := len(validationSet) == 0 || validationSet[policy.Policy.Name][rule.Rule.Name] || selectedSet[policy.Policy.Name][rule.Rule.Name]
+			if selected && !excludedSet[policy.Policy.Name][rule.Rule.Name] {
 				if len(rule.Violations) > 0 {
 					matchResult.Failing += 1
 				} else {
 					matchResult.Passing += 1
 				}
+				resourceResults = mergeResourceResults(resourceResults, ruleResourceResults(rule, resources))
+			}
+
+			// A named observation lists the assertion failure messages of the rule
+			if ruleOutput, ok := ruleOutputs[policy.Policy.Name][rule.Rule.Name]; ok && ruleOutput.Observation != "" {
+				if len(rule.Violations) > 0 {
+					messages := make([]interface{}, 0)
+					for _, msg := range violationMessages(rule.Violations) {
+						messages = append(messages, msg)
+					}
+					observations[ruleOutput.Observation] = messages
+				} else {
+					observations[ruleOutput.Observation] = "PASS"
+				}
+				continue
 			}
 
 			if _, ok := observationSet[policy.Policy.Name][rule.Rule.Name]; len(output.Observations) == 0 || ok {
 				if len(rule.Violations) > 0 {
-					observations[fmt.Sprintf("%s,%s-%d,%d", policy.Policy.Name, rule.Rule.Name, i, j)] = fmt.Sprintf("FAIL: %s", rule.Violations[0].Message)
+					observations[fmt.Sprintf("%s,%s-%d,%d", policy.Policy.Name, rule.Rule.Name, i, j)] = fmt.Sprintf("FAIL: %s", strings.Join(violationMessages(rule.Violations), "; "))
 				} else {
 					observations[fmt.Sprintf("%s,%s-%d,%d", policy.Policy.Name, rule.Rule.Name, i, j)] = "PASS"
 				}
@@ -104,5 +129,142 @@ func GetValidatedAssets(ctx context.Context, kyvernoPolicies *kjson.ValidatingPo
 	}
 
 	matchResult.Observations = observations
+	matchResult.Resources = resourceResults
 	return matchResult, nil
 }
+
+// parseRulePair parses a (Policy, Rule) pair of the form policy-name.rule-name
+func parseRulePair(pair string) (string, string, bool) {
+	parts := strings.Split(pair, ".")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	policyName := strings.TrimSpace(parts[0])
+	ruleName := strings.TrimSpace(parts[1])
+	return policyName, ruleName, policyName != "" && ruleName != ""
+}
+
+// addRulePair adds the (Policy, Rule) pair to the set
+func addRulePair(set map[string]map[string]bool, policyName, ruleName string) {
+	if _, ok := set[policyName]; !ok {
+		set[policyName] = make(map[string]bool)
+	}
+	set[policyName][ruleName] = true
+}
+
+// violationMessages returns the assertion failure messages of the violations, each failed check is
+// reported with the message of its assertion, if any
+func violationMessages(violations matching.Results) []string {
+	var messages []string
+	for _, violation := range violations {
+		if len(violation.ErrorList) == 0 && violation.Message != "" && !slices.Contains(messages, violation.Message) {
+			messages = append(messages, violation.Message)
+		}
+		for _, err := range violation.ErrorList {
+			msg := err.Error()
+			if violation.Message != "" {
+				msg = fmt.Sprintf("%s: %s", violation.Message, msg)
+			}
+			if !slices.Contains(messages, msg) {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return messages
+}
+
+// ruleResourceResults returns the per-resource results of a rule. For each domain resource list the
+// rule checks with a projection, e.g. `~.podsvt`, the elements a violation was found in fail with the
+// violation message and the other elements pass.
+func ruleResourceResults(rule jsonengine.RuleResponse, resources map[string]interface{}) []types.ResourceResult {
+	if rule.Rule.Assert == nil {
+		return nil
+	}
+
+	var results []types.ResourceResult
+	for _, assertion := range slices.Concat(rule.Rule.Assert.All, rule.Rule.Assert.Any) {
+		check, ok := assertion.Check.Value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range check {
+			if !strings.HasPrefix(key, "~") || !strings.Contains(key, ".") {
+				continue
+			}
+			name := strings.Trim(key[strings.Index(key, ".")+1:], "()")
+			items, ok := resources[name].([]interface{})
+			if !ok {
+				continue
+			}
+
+			// Attribute the violations to the elements using their field path, e.g. `~.podsvt[0].metadata`
+			reasons := make(map[int][]string)
+			index := regexp.MustCompile(regexp.QuoteMeta(key) + `\[(\d+)\]`)
+			for _, violation := range rule.Violations {
+				for _, err := range violation.ErrorList {
+					match := index.FindStringSubmatch(err.Field)
+					if match == nil {
+						continue
+					}
+					i, _ := strconv.Atoi(match[1])
+					reason := violation.Message
+					if reason == "" {
+						reason = err.Error()
+					}
+					if !slices.Contains(reasons[i], reason) {
+						reasons[i] = append(reasons[i], reason)
+					}
+				}
+			}
+
+			keyResults := make([]types.ResourceResult, 0, len(items))
+			for i, item := range items {
+				result := resourceIdentity(item, fmt.Sprintf("%s[%d]", name, i))
+				result.Pass = len(reasons[i]) == 0
+				result.Reason = strings.Join(reasons[i], "; ")
+				keyResults = append(keyResults, result)
+			}
+			results = mergeResourceResults(results, keyResults)
+		}
+	}
+	return results
+}
+
+// resourceIdentity returns a result identifying the resource by its kind, namespace and name if it
+// has them, falling back to the given id
+func resourceIdentity(item interface{}, fallback string) types.ResourceResult {
+	result := types.ResourceResult{ID: fallback}
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	result.Kind, _ = fields["kind"].(string)
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		if name, ok := metadata["name"].(string); ok && name != "" {
+			result.ID = name
+		}
+		result.Namespace, _ = metadata["namespace"].(string)
+	}
+	return result
+}
+
+// mergeResourceResults adds the results to the existing ones, a resource checked by several rules
+// only passes if it passes all of them
+func mergeResourceResults(existing, results []types.ResourceResult) []types.ResourceResult {
+	for _, result := range results {
+		i := slices.IndexFunc(existing, func(r types.ResourceResult) bool {
+			return r.ID == result.ID && r.Kind == result.Kind && r.Namespace == result.Namespace
+		})
+		if i < 0 {
+			existing = append(existing, result)
+			continue
+		}
+		if !result.Pass {
+			existing[i].Pass = false
+			if !strings.Contains(existing[i].Reason, result.Reason) {
+				existing[i].Reason = strings.TrimPrefix(existing[i].Reason+"; "+result.Reason, "; ")
+			}
+		}
+	}
+	return existing
+}
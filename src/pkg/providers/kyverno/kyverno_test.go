@@ -0,0 +1,181 @@
+package kyverno_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kjson "github.com/kyverno/kyverno-json/pkg/apis/policy/v1alpha1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/pkg/providers/kyverno"
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+const labelPolicy = `{
+	"metadata": {"name": "labels"},
+	"spec": {
+		"rules": [
+			{
+				"name": "foo-label-exists",
+				"assert": {
+					"all": [
+						{
+							"message": "pod is missing label foo=bar",
+							"check": {"~.podsvt": {"metadata": {"labels": {"foo": "bar"}}}}
+						}
+					]
+				}
+			}
+		]
+	}
+}`
+
+func TestKyvernoResourceResults(t *testing.T) {
+	t.Parallel()
+
+	var policy kjson.ValidatingPolicy
+	require.NoError(t, json.Unmarshal([]byte(labelPolicy), &policy))
+
+	resources := types.DomainResources{
+		"podsvt": []interface{}{
+			map[string]interface{}{
+				"kind":     "Pod",
+				"metadata": map[string]interface{}{"name": "good", "namespace": "test", "labels": map[string]interface{}{"foo": "bar"}},
+			},
+			map[string]interface{}{
+				"kind":     "Pod",
+				"metadata": map[string]interface{}{"name": "bad", "namespace": "test", "labels": map[string]interface{}{"foo": "baz"}},
+			},
+		},
+	}
+
+	result, err := kyverno.GetValidatedAssets(context.Background(), &policy, resources, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Failing)
+	require.Equal(t, []types.ResourceResult{
+		{ID: "good", Kind: "Pod", Namespace: "test", Pass: true},
+		{ID: "bad", Kind: "Pod", Namespace: "test", Pass: false, Reason: "pod is missing label foo=bar"},
+	}, result.Resources)
+}
+
+func TestKyvernoRuleOutputs(t *testing.T) {
+	t.Parallel()
+
+	var policy, pods kjson.ValidatingPolicy
+	require.NoError(t, json.Unmarshal([]byte(labelPolicy), &policy))
+	require.NoError(t, json.Unmarshal([]byte(`{"spec": {"rules": [{"name": "has-pods", "assert": {"all": [{"check": {"(length(podsvt) > `+"`0`"+`)": true}}]}}]}}`), &pods))
+	policy.Spec.Rules = append(policy.Spec.Rules, pods.Spec.Rules...)
+
+	resources := types.DomainResources{
+		"podsvt": []interface{}{
+			map[string]interface{}{
+				"kind":     "Pod",
+				"metadata": map[string]interface{}{"name": "bad", "namespace": "test", "labels": map[string]interface{}{"foo": "baz"}},
+			},
+		},
+	}
+
+	excluded := false
+	output := &kyverno.KyvernoOutput{
+		Rules: []kyverno.KyvernoRuleOutput{
+			{Rule: "labels.foo-label-exists", Observation: "unlabeled", Validation: &excluded},
+			{Rule: "labels.has-pods", Observation: "pods-found"},
+		},
+	}
+
+	result, err := kyverno.GetValidatedAssets(context.Background(), &policy, resources, output)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Passing)
+	require.Equal(t, 0, result.Failing)
+	require.Equal(t, "PASS", result.Observations["pods-found"])
+	require.Equal(t, []interface{}{
+		`pod is missing label foo=bar: all[0].check.~.podsvt[0].metadata.labels.foo: Invalid value: "baz": Expected value: "bar"`,
+	}, result.Observations["unlabeled"])
+	require.Len(t, result.Observations, 2)
+}
+
+func TestKyvernoRuleOutputsSelected(t *testing.T) {
+	t.Parallel()
+
+	var policy, pods kjson.ValidatingPolicy
+	require.NoError(t, json.Unmarshal([]byte(labelPolicy), &policy))
+	require.NoError(t, json.Unmarshal([]byte(`{"spec": {"rules": [{"name": "has-pods", "assert": {"all": [{"check": {"(length(podsvt) > `+"`0`"+`)": true}}]}}]}}`), &pods))
+	policy.Spec.Rules = append(policy.Spec.Rules, pods.Spec.Rules...)
+
+	resources := types.DomainResources{
+		"podsvt": []interface{}{
+			map[string]interface{}{
+				"kind":     "Pod",
+				"metadata": map[string]interface{}{"name": "bad", "namespace": "test", "labels": map[string]interface{}{"foo": "baz"}},
+			},
+		},
+	}
+
+	// Selecting a rule doesn't stop the failing rule that isn't listed from counting
+	selected := true
+	output := &kyverno.KyvernoOutput{
+		Rules: []kyverno.KyvernoRuleOutput{
+			{Rule: "labels.has-pods", Observation: "pods-found", Validation: &selected},
+		},
+	}
+	result, err := kyverno.GetValidatedAssets(context.Background(), &policy, resources, output)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Passing)
+	require.Equal(t, 1, result.Failing)
+
+	// Only the rules of output.validation and the selected rules count once it is set
+	output.Validation = "labels.other-rule"
+	result, err = kyverno.GetValidatedAssets(context.Background(), &policy, resources, output)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Passing)
+	require.Equal(t, 0, result.Failing)
+}
+
+func TestCreateKyvernoProviderRuleOutputs(t *testing.T) {
+	t.Parallel()
+
+	var policy kjson.ValidatingPolicy
+	require.NoError(t, json.Unmarshal([]byte(labelPolicy), &policy))
+
+	tests := []struct {
+		name    string
+		rules   []kyverno.KyvernoRuleOutput
+		wantErr bool
+	}{
+		{
+			name:  "valid rule",
+			rules: []kyverno.KyvernoRuleOutput{{Rule: "labels.foo-label-exists", Observation: "unlabeled"}},
+		},
+		{
+			name:    "invalid pair",
+			rules:   []kyverno.KyvernoRuleOutput{{Rule: "foo-label-exists"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown rule",
+			rules:   []kyverno.KyvernoRuleOutput{{Rule: "labels.missing"}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate observation",
+			rules: []kyverno.KyvernoRuleOutput{
+				{Rule: "labels.foo-label-exists", Observation: "unlabeled"},
+				{Rule: "labels.foo-label-exists", Observation: "unlabeled"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kyverno.CreateKyvernoProvider(context.Background(), &kyverno.KyvernoSpec{
+				Policy: &policy,
+				Output: &kyverno.KyvernoOutput{Rules: tt.rules},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateKyvernoProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
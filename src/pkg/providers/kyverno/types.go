@@ -3,6 +3,7 @@ package kyverno
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/defenseunicorns/lula/src/types"
 	kjson "github.com/kyverno/kyverno-json/pkg/apis/policy/v1alpha1"
@@ -23,6 +24,27 @@ func CreateKyvernoProvider(_ context.Context, spec *KyvernoSpec) (types.Provider
 		return nil, fmt.Errorf("policy is nil")
 	}
 
+	if spec.Output != nil {
+		observationNames := make(map[string]bool)
+		for _, ruleOutput := range spec.Output.Rules {
+			policyName, ruleName, ok := parseRulePair(ruleOutput.Rule)
+			if !ok {
+				return nil, fmt.Errorf("invalid rule %q, must be a (Policy, Rule) pair: policy-name.rule-name", ruleOutput.Rule)
+			}
+			if policyName != spec.Policy.Name || !slices.ContainsFunc(spec.Policy.Spec.Rules, func(r kjson.ValidatingRule) bool {
+				return r.Name == ruleName
+			}) {
+				return nil, fmt.Errorf("rule %q not found in policy", ruleOutput.Rule)
+			}
+			if ruleOutput.Observation != "" {
+				if observationNames[ruleOutput.Observation] {
+					return nil, fmt.Errorf("duplicate observation name %q", ruleOutput.Observation)
+				}
+				observationNames[ruleOutput.Observation] = true
+			}
+		}
+	}
+
 	return KyvernoProvider{
 		Spec: spec,
 	}, nil
@@ -36,12 +58,29 @@ func (k KyvernoProvider) Evaluate(ctx context.Context, resources types.DomainRes
 	return results, nil
 }
 
+// IsExecutable returns false
+func (k KyvernoProvider) IsExecutable() bool {
+	return false
+}
+
 type KyvernoSpec struct {
 	Policy *kjson.ValidatingPolicy `json:"policy" yaml:"policy"`
 	Output *KyvernoOutput          `json:"output,omitempty" yaml:"output,omitempty"`
 }
 
 type KyvernoOutput struct {
-	Validation   string   `json:"validation" yaml:"validation"`
-	Observations []string `json:"observations" yaml:"observations"`
+	Validation   string              `json:"validation" yaml:"validation"`
+	Observations []string            `json:"observations" yaml:"observations"`
+	Rules        []KyvernoRuleOutput `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// KyvernoRuleOutput shapes the output of a single (Policy, Rule) pair
+type KyvernoRuleOutput struct {
+	// Rule is the (Policy, Rule) pair, i.e. policy-name.rule-name
+	Rule string `json:"rule" yaml:"rule"`
+	// Observation is the name of the observation the result of the rule is added as, optional
+	Observation string `json:"observation,omitempty" yaml:"observation,omitempty"`
+	// Validation excludes the rule from the validation if false, or adds it to the rules of
+	// KyvernoOutput.Validation if true, optional
+	Validation *bool `json:"validation,omitempty" yaml:"validation,omitempty"`
 }
@@ -9,7 +9,9 @@ import (
 	"github.com/defenseunicorns/lula/src/pkg/message"
 	"github.com/defenseunicorns/lula/src/types"
 	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
 )
 
 var (
@@ -20,8 +22,38 @@ var (
 // mainPolicyModuleName is the name of the OPA module containing the main policy from the spec.rego field.
 const mainPolicyModuleName = "validate.rego"
 
+type evalOptions struct {
+	data         map[string]interface{}
+	bundles      []*bundle.Bundle
+	capabilities *ast.Capabilities
+}
+
+// EvalOption configures the evaluation of a rego policy
+type EvalOption func(*evalOptions)
+
+// WithData sets the data document the policy is evaluated with, available under `data`
+func WithData(data map[string]interface{}) EvalOption {
+	return func(opts *evalOptions) {
+		opts.data = data
+	}
+}
+
+// WithBundles adds the modules and data of the bundles to the policy
+func WithBundles(bundles []*bundle.Bundle) EvalOption {
+	return func(opts *evalOptions) {
+		opts.bundles = bundles
+	}
+}
+
+// WithCapabilities restricts the built-in functions the policy can use
+func WithCapabilities(capabilities *ast.Capabilities) EvalOption {
+	return func(opts *evalOptions) {
+		opts.capabilities = capabilities
+	}
+}
+
 // GetValidatedAssets performs the validation of the dataset against the given rego policy
-func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[string]string, dataset map[string]interface{}, output *OpaOutput) (types.Result, error) {
+func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[string]string, dataset map[string]interface{}, output *OpaOutput, opts ...EvalOption) (types.Result, error) {
 	var matchResult types.Result
 
 	if len(dataset) == 0 {
@@ -32,16 +64,70 @@ func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[
 		output = &OpaOutput{}
 	}
 
+	config := &evalOptions{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	modules := make(map[string]string, len(regoModules)+1)
 	for k, v := range regoModules {
 		modules[k] = v
 	}
 	modules[mainPolicyModuleName] = regoPolicy
 
-	compiler, err := ast.CompileModules(modules)
-	if err != nil {
-		message.Debugf("failed to compile rego policy: %s", err.Error())
-		return matchResult, fmt.Errorf("%w: %w", ErrCompileRego, err)
+	parsed := make(map[string]*ast.Module, len(modules))
+	for name, module := range modules {
+		pm, err := ast.ParseModuleWithOpts(name, module, ast.ParserOptions{})
+		if err != nil {
+			message.Debugf("failed to parse rego policy: %s", err.Error())
+			return matchResult, fmt.Errorf("%w: %w", ErrCompileRego, err)
+		}
+		parsed[name] = pm
+	}
+
+	data := make(map[string]interface{})
+	if err := mergeData(data, config.data, ""); err != nil {
+		return matchResult, err
+	}
+	for i, b := range config.bundles {
+		for _, mf := range b.Modules {
+			parsed[fmt.Sprintf("bundle-%d%s", i, mf.Path)] = mf.Parsed
+		}
+		if err := mergeData(data, b.Data, ""); err != nil {
+			return matchResult, err
+		}
+	}
+	store := inmem.NewFromObject(data)
+
+	compiler := ast.NewCompiler()
+	if config.capabilities != nil {
+		compiler = compiler.WithCapabilities(config.capabilities)
+	}
+	compiler.Compile(parsed)
+	if compiler.Failed() {
+		message.Debugf("failed to compile rego policy: %s", compiler.Errors.Error())
+		return matchResult, fmt.Errorf("%w: %w", ErrCompileRego, compiler.Errors)
+	}
+
+	// A validation that does not apply is neither passing nor failing
+	if output.NotApplicable != "" {
+		regoCalcNA := rego.New(
+			rego.Query(fmt.Sprintf("data.%s", output.NotApplicable)),
+			rego.Compiler(compiler),
+			rego.Input(dataset),
+			rego.Store(store),
+		)
+
+		resultNA, err := regoCalcNA.Eval(ctx)
+		if err != nil {
+			return matchResult, fmt.Errorf("%w: %w", ErrEvaluateRego, err)
+		}
+		if len(resultNA) != 0 {
+			if notApplicable, ok := resultNA[0].Expressions[0].Value.(bool); ok && notApplicable {
+				matchResult.State = "not-applicable"
+				return matchResult, nil
+			}
+		}
 	}
 
 	// Get validation decision
@@ -54,6 +140,7 @@ func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[
 		rego.Query(fmt.Sprintf("data.%s", validation)),
 		rego.Compiler(compiler),
 		rego.Input(dataset),
+		rego.Store(store),
 	)
 
 	resultValid, err := regoCalcValid.Eval(ctx)
@@ -75,13 +162,14 @@ func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[
 		matchResult.Failing += 1
 	}
 
-	// Get additional observations, if they exist - only supports string output
-	observations := make(map[string]string)
+	// Get additional observations, if they exist - any JSON value is kept as-is
+	observations := make(map[string]interface{})
 	for _, obv := range output.Observations {
 		regoCalcObv := rego.New(
 			rego.Query(fmt.Sprintf("data.%s", obv)),
 			rego.Compiler(compiler),
 			rego.Input(dataset),
+			rego.Store(store),
 		)
 
 		resultObv, err := regoCalcObv.Eval(ctx)
@@ -90,16 +178,69 @@ func GetValidatedAssets(ctx context.Context, regoPolicy string, regoModules map[
 		}
 		// To do: check if resultObv is empty - basically some extra error handling if a user defines an output but it's not coming out of the rego
 		if len(resultObv) != 0 {
-			if matched, ok := resultObv[0].Expressions[0].Value.(string); ok {
-				observations[obv] = matched
-			} else {
-				message.Debugf("Observation field %s expected string and got %s", obv, reflect.TypeOf(resultObv[0].Expressions[0].Value))
-			}
+			observations[obv] = resultObv[0].Expressions[0].Value
 		} else {
 			message.Debugf("Observation field %s not output from rego", obv)
 		}
 	}
 	matchResult.Observations = observations
 
+	// Get the per-resource results, if they exist
+	resources := "validate.resources"
+	if output.Resources != "" {
+		resources = output.Resources
+	}
+
+	regoCalcResources := rego.New(
+		rego.Query(fmt.Sprintf("data.%s", resources)),
+		rego.Compiler(compiler),
+		rego.Input(dataset),
+		rego.Store(store),
+	)
+
+	resultResources, err := regoCalcResources.Eval(ctx)
+	if err != nil {
+		return matchResult, fmt.Errorf("%w: %w", ErrEvaluateRego, err)
+	}
+	if len(resultResources) != 0 {
+		matchResult.Resources = toResourceResults(resources, resultResources[0].Expressions[0].Value)
+	}
+
 	return matchResult, nil
 }
+
+// toResourceResults converts the value of the resources output to per-resource results, entries
+// without an id or a boolean pass are skipped
+func toResourceResults(path string, value interface{}) []types.ResourceResult {
+	entries, ok := value.([]interface{})
+	if !ok {
+		message.Debugf("Resources field %s expected array and got %s", path, reflect.TypeOf(value))
+		return nil
+	}
+
+	resources := make([]types.ResourceResult, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			message.Debugf("Resources field %s entry expected object and got %s", path, reflect.TypeOf(entry))
+			continue
+		}
+		id, _ := fields["id"].(string)
+		pass, ok := fields["pass"].(bool)
+		if id == "" || !ok {
+			message.Debugf("Resources field %s entry requires an id and a boolean pass: %v", path, fields)
+			continue
+		}
+		kind, _ := fields["kind"].(string)
+		namespace, _ := fields["namespace"].(string)
+		reason, _ := fields["reason"].(string)
+		resources = append(resources, types.ResourceResult{
+			ID:        id,
+			Kind:      kind,
+			Namespace: namespace,
+			Pass:      pass,
+			Reason:    reason,
+		})
+	}
+	return resources
+}
@@ -2,10 +2,19 @@ package opa_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/loader"
+
 	"github.com/defenseunicorns/lula/src/pkg/providers/opa"
+	"github.com/defenseunicorns/lula/src/types"
 )
 
 func TestOpaModules(t *testing.T) {
@@ -86,6 +95,347 @@ func TestOpaModules(t *testing.T) {
 	}
 }
 
+func TestOpaNotApplicable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rego        string
+		wantState   string
+		wantPassing int
+	}{
+		{
+			name:      "not applicable",
+			rego:      "package validate\n\ndefault validate = true\n\nskip = true",
+			wantState: "not-applicable",
+		},
+		{
+			name:        "applicable",
+			rego:        "package validate\n\ndefault validate = true\n\nskip = false",
+			wantPassing: 1,
+		},
+		{
+			name:        "undefined",
+			rego:        "package validate\n\ndefault validate = true",
+			wantPassing: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{
+				Rego:   tt.rego,
+				Output: &opa.OpaOutput{NotApplicable: "validate.skip"},
+			})
+			if err != nil {
+				t.Fatalf("CreateOpaProvider() error: %v", err)
+			}
+
+			result, err := provider.Evaluate(ctx, dummyPod)
+			if err != nil {
+				t.Fatalf("Evaluate() error: %v", err)
+			}
+
+			if result.State != tt.wantState {
+				t.Errorf("State = %q, want %q", result.State, tt.wantState)
+			}
+			if result.Passing != tt.wantPassing {
+				t.Errorf("Passing = %d, want %d", result.Passing, tt.wantPassing)
+			}
+		})
+	}
+}
+
+func TestOpaResources(t *testing.T) {
+	t.Parallel()
+
+	rego := `package validate
+
+default validate = false
+
+resources = [
+	{"id": "lula-pod", "kind": "Pod", "namespace": "test", "pass": true},
+	{"id": "other-pod", "kind": "Pod", "pass": false, "reason": "missing label"},
+	{"kind": "Pod", "pass": true},
+	"invalid",
+]`
+
+	ctx := context.Background()
+	provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{Rego: rego})
+	if err != nil {
+		t.Fatalf("CreateOpaProvider() error: %v", err)
+	}
+
+	result, err := provider.Evaluate(ctx, dummyPod)
+	if err != nil {
+		t.Fatalf("Evaluate() error: %v", err)
+	}
+
+	want := []types.ResourceResult{
+		{ID: "lula-pod", Kind: "Pod", Namespace: "test", Pass: true},
+		{ID: "other-pod", Kind: "Pod", Pass: false, Reason: "missing label"},
+	}
+	if !reflect.DeepEqual(result.Resources, want) {
+		t.Errorf("Resources = %v, want %v", result.Resources, want)
+	}
+}
+
+func TestOpaObservations(t *testing.T) {
+	t.Parallel()
+
+	rego := `package validate
+
+default validate = false
+
+name = "lula"
+
+offenders = ["pod-a", "pod-b"]
+
+count = 2
+
+summary = {"checked": 3, "failed": offenders}`
+
+	ctx := context.Background()
+	provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{
+		Rego: rego,
+		Output: &opa.OpaOutput{
+			Observations: []string{"validate.name", "validate.offenders", "validate.count", "validate.summary", "validate.undefined"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateOpaProvider() error: %v", err)
+	}
+
+	result, err := provider.Evaluate(ctx, dummyPod)
+	if err != nil {
+		t.Fatalf("Evaluate() error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"validate.name":      "lula",
+		"validate.offenders": []interface{}{"pod-a", "pod-b"},
+		"validate.count":     json.Number("2"),
+		"validate.summary": map[string]interface{}{
+			"checked": json.Number("3"),
+			"failed":  []interface{}{"pod-a", "pod-b"},
+		},
+	}
+	if !reflect.DeepEqual(result.Observations, want) {
+		t.Errorf("Observations = %v, want %v", result.Observations, want)
+	}
+}
+
+func TestOpaData(t *testing.T) {
+	t.Parallel()
+
+	rego := "package validate\n\nimport rego.v1\n\ndefault validate := false\n\nvalidate if {\n\t\"ghcr.io\" in data.registries.allowed\n\tdata.lists.labels.lula == \"true\"\n}"
+
+	tests := []struct {
+		name        string
+		data        []opa.OpaData
+		wantErr     error
+		wantPassing int
+	}{
+		{
+			name: "file and inline documents",
+			data: []opa.OpaData{
+				{Path: "registries", File: "testdata/registries.yaml"},
+				{Path: "lists.labels", Document: map[string]interface{}{"lula": "true"}},
+			},
+			wantPassing: 1,
+		},
+		{
+			name: "missing document",
+			data: []opa.OpaData{
+				{Path: "registries", File: "testdata/registries.yaml"},
+			},
+		},
+		{
+			name: "conflicting documents",
+			data: []opa.OpaData{
+				{Path: "registries", File: "testdata/registries.yaml"},
+				{Path: "registries.allowed", Document: []interface{}{"ghcr.io"}},
+			},
+			wantErr: opa.ErrConflictingData,
+		},
+		{
+			name: "invalid file",
+			data: []opa.OpaData{
+				{Path: "registries", File: "testdata/missing.yaml"},
+			},
+			wantErr: opa.ErrDownloadData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{Rego: rego, Data: tt.data})
+			if err != nil {
+				t.Fatalf("CreateOpaProvider() error: %v", err)
+			}
+
+			result, err := provider.Evaluate(ctx, dummyPod)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.Passing != tt.wantPassing {
+				t.Errorf("Passing = %d, want %d", result.Passing, tt.wantPassing)
+			}
+		})
+	}
+}
+
+func TestOpaDataEvaluateTwice(t *testing.T) {
+	t.Parallel()
+
+	rego := "package validate\n\nimport rego.v1\n\ndefault validate := false\n\nvalidate if {\n\tdata.lists.a == 1\n\tdata.lists.b == 2\n\tdata.lib.required_label == \"lula\"\n}"
+	spec := &opa.OpaSpec{
+		Rego: rego,
+		Data: []opa.OpaData{
+			{Path: "lists", Document: map[string]interface{}{"a": 1}},
+			{Path: "lists.b", Document: 2},
+			{Path: "lib.extra", Document: true},
+		},
+		Bundles: []string{"testdata/bundle"},
+	}
+
+	ctx := context.Background()
+	provider, err := opa.CreateOpaProvider(ctx, spec)
+	if err != nil {
+		t.Fatalf("CreateOpaProvider() error: %v", err)
+	}
+
+	// Evaluating must not modify the documents, so the validation can be evaluated again
+	for i := range 2 {
+		result, err := provider.Evaluate(ctx, dummyPod)
+		if err != nil {
+			t.Fatalf("Evaluate() %d error: %v", i, err)
+		}
+		if result.Passing != 1 {
+			t.Errorf("Evaluate() %d Passing = %d, want 1", i, result.Passing)
+		}
+	}
+
+	if want := map[string]interface{}{"a": 1}; !reflect.DeepEqual(spec.Data[0].Document, want) {
+		t.Errorf("Document = %v, want %v", spec.Data[0].Document, want)
+	}
+}
+
+func TestOpaBundles(t *testing.T) {
+	t.Parallel()
+
+	rego := "package validate\n\nimport data.lib.labels\n\nvalidate { labels.has_required_label(input.pod) }"
+
+	// Write the bundle directory as a tarball too
+	b, err := loader.NewFileLoader().AsBundle("testdata/bundle")
+	if err != nil {
+		t.Fatalf("AsBundle() error: %v", err)
+	}
+	tarball := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(tarball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bundle.NewWriter(f).Write(*b); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for name, path := range map[string]string{"directory": "testdata/bundle", "tarball": tarball} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{Rego: rego, Bundles: []string{path}})
+			if err != nil {
+				t.Fatalf("CreateOpaProvider() error: %v", err)
+			}
+
+			result, err := provider.Evaluate(ctx, dummyPod)
+			if err != nil {
+				t.Fatalf("Evaluate() error: %v", err)
+			}
+			if result.Passing != 1 {
+				t.Errorf("Passing = %d, want 1", result.Passing)
+			}
+		})
+	}
+
+	t.Run("missing bundle", func(t *testing.T) {
+		ctx := context.Background()
+		provider, err := opa.CreateOpaProvider(ctx, &opa.OpaSpec{Rego: rego, Bundles: []string{"testdata/missing"}})
+		if err != nil {
+			t.Fatalf("CreateOpaProvider() error: %v", err)
+		}
+
+		// a missing local path isn't downloaded
+		_, err = provider.Evaluate(ctx, dummyPod)
+		if !errors.Is(err, opa.ErrLoadBundle) || !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Evaluate() error = %v, want %v and %v", err, opa.ErrLoadBundle, fs.ErrNotExist)
+		}
+	})
+}
+
+func TestOpaBuiltins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		spec           *opa.OpaSpec
+		wantExecutable bool
+		wantErr        error
+	}{
+		{
+			name: "network built-in disabled",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\nvalidate { net.lookup_ip_addr(\"localhost\") }",
+			},
+			wantErr: opa.ErrCompileRego,
+		},
+		{
+			name: "runtime built-in disabled",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\nvalidate { opa.runtime() }",
+			},
+			wantErr: opa.ErrCompileRego,
+		},
+		{
+			name: "runtime built-in executable",
+			spec: &opa.OpaSpec{
+				Rego:       "package validate\n\nvalidate { opa.runtime() }",
+				Executable: true,
+			},
+			wantExecutable: true,
+		},
+		{
+			name: "additional disabled built-in",
+			spec: &opa.OpaSpec{
+				Rego:             "package validate\n\nvalidate { time.now_ns() > 0 }",
+				DisabledBuiltins: []string{"time.now_ns"},
+			},
+			wantErr: opa.ErrCompileRego,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, err := opa.CreateOpaProvider(ctx, tt.spec)
+			if err != nil {
+				t.Fatalf("CreateOpaProvider() error: %v", err)
+			}
+			if provider.IsExecutable() != tt.wantExecutable {
+				t.Errorf("IsExecutable() = %t, want %t", provider.IsExecutable(), tt.wantExecutable)
+			}
+
+			_, err = provider.Evaluate(ctx, dummyPod)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 var dummyPod = map[string]interface{}{
 	"pod": map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -0,0 +1,7 @@
+package lib.labels
+
+import rego.v1
+
+has_required_label(pod) if {
+    pod.metadata.labels[data.lib.required_label] == "true"
+}
@@ -6,22 +6,42 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/util"
+	"sigs.k8s.io/yaml"
+
 	"github.com/defenseunicorns/lula/src/pkg/common/network"
 	"github.com/defenseunicorns/lula/src/types"
 )
 
 var (
-	ErrNilSpec                = errors.New("spec is nil")
-	ErrEmptyRego              = errors.New("rego policy cannot be empty")
-	ErrInvalidValidationPath  = errors.New("validation field must be a json path")
-	ErrInvalidObservationPath = errors.New("observation field must be a json path")
-	ErrDownloadModule         = errors.New("error downloading module")
-	ErrReadModule             = errors.New("error reading module")
-	ErrReservedModuleName     = errors.New("module name is reserved and cannot be used in custom modules")
+	ErrNilSpec                  = errors.New("spec is nil")
+	ErrEmptyRego                = errors.New("rego policy cannot be empty")
+	ErrInvalidValidationPath    = errors.New("validation field must be a json path")
+	ErrInvalidObservationPath   = errors.New("observation field must be a json path")
+	ErrInvalidNotApplicablePath = errors.New("not-applicable field must be a json path")
+	ErrInvalidResourcesPath     = errors.New("resources field must be a json path")
+	ErrDownloadModule           = errors.New("error downloading module")
+	ErrReadModule               = errors.New("error reading module")
+	ErrReservedModuleName       = errors.New("module name is reserved and cannot be used in custom modules")
+	ErrInvalidDataPath          = errors.New("data path must be a dot separated path")
+	ErrInvalidData              = errors.New("data document must set exactly one of file or document")
+	ErrConflictingData          = errors.New("conflicting data documents")
+	ErrDownloadData             = errors.New("error downloading data document")
+	ErrReadData                 = errors.New("error reading data document")
+	ErrLoadBundle               = errors.New("error loading bundle")
+	ErrUnknownBuiltin           = errors.New("unknown built-in function")
 )
 
+// executableBuiltins are the built-in functions that make network calls or read the runtime
+// environment, these are only enabled for executable policies
+var executableBuiltins = []string{"http.send", "net.lookup_ip_addr", "opa.runtime"}
+
 type OpaProvider struct {
 	// Spec is the specification of the OPA policy
 	Spec *OpaSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
@@ -43,6 +63,16 @@ func CreateOpaProvider(_ context.Context, spec *OpaSpec) (types.Provider, error)
 				return nil, ErrInvalidValidationPath
 			}
 		}
+		if spec.Output.NotApplicable != "" {
+			if !strings.Contains(spec.Output.NotApplicable, ".") {
+				return nil, ErrInvalidNotApplicablePath
+			}
+		}
+		if spec.Output.Resources != "" {
+			if !strings.Contains(spec.Output.Resources, ".") {
+				return nil, ErrInvalidResourcesPath
+			}
+		}
 		if spec.Output.Observations != nil {
 			for _, observation := range spec.Output.Observations {
 				if !strings.Contains(observation, ".") {
@@ -52,11 +82,57 @@ func CreateOpaProvider(_ context.Context, spec *OpaSpec) (types.Provider, error)
 		}
 	}
 
+	for _, data := range spec.Data {
+		if data.Path == "" || slices.Contains(strings.Split(data.Path, "."), "") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDataPath, data.Path)
+		}
+		if (data.File == "") == (data.Document == nil) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidData, data.Path)
+		}
+	}
+
+	for _, name := range spec.DisabledBuiltins {
+		if !slices.ContainsFunc(ast.CapabilitiesForThisVersion().Builtins, func(b *ast.Builtin) bool {
+			return b.Name == name
+		}) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownBuiltin, name)
+		}
+	}
+
 	return OpaProvider{
 		Spec: spec,
 	}, nil
 }
 
+// IsExecutable returns true if the policy is allowed to use the built-in functions that make
+// network calls or read the runtime environment
+func (o OpaProvider) IsExecutable() bool {
+	return o.Spec.Executable
+}
+
+// capabilities returns the capabilities of the policy, i.e. the built-in functions it can use
+func (o OpaProvider) capabilities() *ast.Capabilities {
+	disabled := slices.Clone(o.Spec.DisabledBuiltins)
+	if !o.Spec.Executable {
+		disabled = append(disabled, executableBuiltins...)
+	}
+
+	capabilities := ast.CapabilitiesForThisVersion()
+	capabilities.Builtins = slices.DeleteFunc(capabilities.Builtins, func(b *ast.Builtin) bool {
+		return slices.Contains(disabled, b.Name)
+	})
+	return capabilities
+}
+
+// workDir returns the directory relative paths are resolved against
+func workDir(ctx context.Context) string {
+	workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
+	if !ok { // if unset, assume lula is already working in the same directory the inputFile is in
+		workDir = "."
+	}
+	return workDir
+}
+
 // loadModules downloads the modules specified in the modulePaths map and returns
 // a map of the module name to the module content.
 func loadModules(ctx context.Context, modulePaths map[string]string) (map[string]string, error) {
@@ -68,11 +144,6 @@ func loadModules(ctx context.Context, modulePaths map[string]string) (map[string
 		return nil, fmt.Errorf("%w: %s", ErrReservedModuleName, mainPolicyModuleName)
 	}
 
-	workDir, ok := ctx.Value(types.LulaValidationWorkDir).(string)
-	if !ok { // if unset, assume lula is already working in the same directory the inputFile is in
-		workDir = "."
-	}
-
 	dst, err := os.MkdirTemp("", "lula-modules-")
 	if err != nil {
 		return nil, err
@@ -82,7 +153,7 @@ func loadModules(ctx context.Context, modulePaths map[string]string) (map[string
 	loadedModules := make(map[string]string)
 	for name, src := range modulePaths {
 		dst := filepath.Join(dst, filepath.Base(src))
-		tmp, err := network.DownloadFile(ctx, dst, src, workDir)
+		tmp, err := network.DownloadFile(ctx, dst, src, workDir(ctx))
 		if err != nil {
 			return nil, fmt.Errorf("%w %s: %w", ErrDownloadModule, name, err)
 		}
@@ -96,12 +167,134 @@ func loadModules(ctx context.Context, modulePaths map[string]string) (map[string
 	return loadedModules, nil
 }
 
+// loadData loads the data documents and returns them merged into a single document, the root of
+// `data` in the policy.
+func loadData(ctx context.Context, documents []OpaData) (map[string]interface{}, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	dst, err := os.MkdirTemp("", "lula-data-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dst)
+
+	data := make(map[string]interface{})
+	for _, document := range documents {
+		value := document.Document
+		if document.File != "" {
+			dst := filepath.Join(dst, filepath.Base(document.File))
+			tmp, err := network.DownloadFile(ctx, dst, document.File, workDir(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrDownloadData, document.Path, err)
+			}
+			content, err := os.ReadFile(filepath.Clean(tmp))
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrReadData, document.Path, err)
+			}
+			if err := yaml.Unmarshal(content, &value); err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrReadData, document.Path, err)
+			}
+		}
+
+		// Nest the document under its path, e.g. `lists.registries` -> {"lists": {"registries": value}}
+		keys := strings.Split(document.Path, ".")
+		for i := len(keys) - 1; i >= 0; i-- {
+			value = map[string]interface{}{keys[i]: value}
+		}
+		if err := mergeData(data, value.(map[string]interface{}), ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// loadBundles loads the OPA bundles, either a directory or a tarball. Local paths are loaded in
+// place while remote sources are downloaded as a tarball.
+func loadBundles(ctx context.Context, bundlePaths []string) ([]*bundle.Bundle, error) {
+	if len(bundlePaths) == 0 {
+		return nil, nil
+	}
+
+	dst, err := os.MkdirTemp("", "lula-bundles-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dst)
+
+	bundles := make([]*bundle.Bundle, 0, len(bundlePaths))
+	for _, src := range bundlePaths {
+		path := src
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir(ctx), path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			if !isRemote(src) {
+				return nil, fmt.Errorf("%w %s: %w", ErrLoadBundle, src, err)
+			}
+			dst := filepath.Join(dst, filepath.Base(src))
+			path, err = network.DownloadFile(ctx, dst, src, workDir(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrLoadBundle, src, err)
+			}
+		}
+
+		b, err := loader.NewFileLoader().WithSkipBundleVerification(true).AsBundle(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrLoadBundle, src, err)
+		}
+		bundles = append(bundles, b)
+	}
+
+	return bundles, nil
+}
+
+// isRemote returns true if the source is a URL or uses a go-getter forced getter (e.g. git::).
+func isRemote(src string) bool {
+	return strings.Contains(src, "://") || strings.Contains(src, "::")
+}
+
+// mergeData merges a copy of the src document into dst, so that src is never modified by later
+// merges. Documents at the same path must both be objects.
+func mergeData(dst, src map[string]interface{}, path string) error {
+	for k, v := range src {
+		existing, ok := dst[k]
+		if !ok {
+			if err := util.RoundTrip(&v); err != nil {
+				return fmt.Errorf("%w data%s.%s: %w", ErrReadData, path, k, err)
+			}
+			dst[k] = v
+			continue
+		}
+		existingObject, ok := existing.(map[string]interface{})
+		object, isObject := v.(map[string]interface{})
+		if !ok || !isObject {
+			return fmt.Errorf("%w at data%s.%s", ErrConflictingData, path, k)
+		}
+		if err := mergeData(existingObject, object, path+"."+k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o OpaProvider) Evaluate(ctx context.Context, resources types.DomainResources) (types.Result, error) {
 	modules, err := loadModules(ctx, o.Spec.Modules)
 	if err != nil {
 		return types.Result{}, err
 	}
-	results, err := GetValidatedAssets(ctx, o.Spec.Rego, modules, resources, o.Spec.Output)
+	data, err := loadData(ctx, o.Spec.Data)
+	if err != nil {
+		return types.Result{}, err
+	}
+	bundles, err := loadBundles(ctx, o.Spec.Bundles)
+	if err != nil {
+		return types.Result{}, err
+	}
+	results, err := GetValidatedAssets(ctx, o.Spec.Rego, modules, resources, o.Spec.Output,
+		WithData(data), WithBundles(bundles), WithCapabilities(o.capabilities()))
 	if err != nil {
 		return types.Result{}, err
 	}
@@ -116,14 +309,41 @@ type OpaSpec struct {
 	// module and the value is the file with the contents of the module. The `validate.rego` module
 	// name is reserved and cannot be used in custom modules.
 	Modules map[string]string `json:"modules,omitempty" yaml:"modules,omitempty"`
+	// Optional: Data is a list of data documents available to the policy under `data`
+	Data []OpaData `json:"data,omitempty" yaml:"data,omitempty"`
+	// Optional: Bundles is a list of OPA bundles to include, each a directory or a tarball. The
+	// modules and data of the bundles are available to the policy.
+	Bundles []string `json:"bundles,omitempty" yaml:"bundles,omitempty"`
+	// Optional: Executable enables the built-in functions that make network calls or read the runtime
+	// environment, i.e. http.send, net.lookup_ip_addr and opa.runtime. Executable validations require
+	// confirmation before they are run.
+	Executable bool `json:"executable,omitempty" yaml:"executable,omitempty"`
+	// Optional: DisabledBuiltins is a list of additional built-in functions the policy cannot use
+	DisabledBuiltins []string `json:"disabled-builtins,omitempty" yaml:"disabled-builtins,omitempty"`
 	// Optional: Output is the output of the OPA policy
 	Output *OpaOutput `json:"output,omitempty" yaml:"output,omitempty"`
 }
 
+// OpaData is a data document available to the policy, either read from a file or given inline
+type OpaData struct {
+	// Required: Path is the dot separated path the document is available at, e.g. `lists.registries`
+	// is available as `data.lists.registries`
+	Path string `json:"path" yaml:"path"`
+	// Optional: File is the JSON or YAML file with the document
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Optional: Document is the inline document
+	Document interface{} `json:"document,omitempty" yaml:"document,omitempty"`
+}
+
 // OpaOutput Defines the output structure for OPA validation results, including validation status and additional observations.
 type OpaOutput struct {
 	// optional: Specifies the JSON path to a boolean value indicating the validation result.
 	Validation string `json:"validation" yaml:"validation"`
-	// optional: any additional observations to include (fields must resolve to strings)
+	// optional: Specifies the JSON path to a boolean value marking the validation as not applicable, e.g., when no matching resources exist.
+	NotApplicable string `json:"not-applicable,omitempty" yaml:"not-applicable,omitempty"`
+	// optional: any additional observations to include (fields may resolve to any JSON value)
 	Observations []string `json:"observations" yaml:"observations"`
+	// optional: Specifies the JSON path to a list of per-resource results, each an object with an id, kind,
+	// namespace, pass and reason. Defaults to validate.resources, which is ignored if undefined.
+	Resources string `json:"resources,omitempty" yaml:"resources,omitempty"`
 }
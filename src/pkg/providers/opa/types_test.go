@@ -66,6 +66,48 @@ func TestCreateOpaProvider(t *testing.T) {
 			},
 			wantErr: opa.ErrInvalidObservationPath,
 		},
+		{
+			name: "invalid not-applicable path",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\ndefault validate = false",
+				Output: &opa.OpaOutput{
+					NotApplicable: "invalid-path",
+				},
+			},
+			wantErr: opa.ErrInvalidNotApplicablePath,
+		},
+		{
+			name: "invalid data path",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\ndefault validate = false",
+				Data: []opa.OpaData{{Path: "lists..registries", Document: []interface{}{}}},
+			},
+			wantErr: opa.ErrInvalidDataPath,
+		},
+		{
+			name: "data without a document",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\ndefault validate = false",
+				Data: []opa.OpaData{{Path: "registries"}},
+			},
+			wantErr: opa.ErrInvalidData,
+		},
+		{
+			name: "data with a file and a document",
+			spec: &opa.OpaSpec{
+				Rego: "package validate\n\ndefault validate = false",
+				Data: []opa.OpaData{{Path: "registries", File: "registries.yaml", Document: []interface{}{}}},
+			},
+			wantErr: opa.ErrInvalidData,
+		},
+		{
+			name: "unknown disabled built-in",
+			spec: &opa.OpaSpec{
+				Rego:             "package validate\n\ndefault validate = false",
+				DisabledBuiltins: []string{"not.a_builtin"},
+			},
+			wantErr: opa.ErrUnknownBuiltin,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,8 +91,8 @@ func TestOutputs(t *testing.T) {
 					}
 				}
 				if strings.Contains(o.Description, "ID-2") {
-					// Check remarks are empty due to incorrect observation formats
-					if o.RelevantEvidence != nil && (*o.RelevantEvidence)[0].Remarks != "" {
+					// Check non-string observations are kept and missing observations are skipped
+					if o.RelevantEvidence != nil && (*o.RelevantEvidence)[0].Remarks != "validate.test: false\n" {
 						t.Fatal("Failed to validate payload.output observations for ID-2")
 					}
 				}
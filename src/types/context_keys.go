@@ -4,4 +4,5 @@ type contextKey int
 
 const (
 	LulaValidationWorkDir contextKey = iota
+	LulaResourceCache
 )
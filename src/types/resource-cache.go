@@ -0,0 +1,116 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/defenseunicorns/lula/src/pkg/message"
+)
+
+// ResourceCache caches the resources collected by domains for the duration of
+// a run, so identical collections across validations happen once. Cached
+// values are shared between validations and must not be modified.
+type ResourceCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewResourceCache returns an empty resource cache
+func NewResourceCache() *ResourceCache {
+	return &ResourceCache{entries: make(map[string]*cacheEntry)}
+}
+
+// WithResourceCache returns a context carrying the resource cache. A nil cache
+// disables caching for the collections made with the context.
+func WithResourceCache(ctx context.Context, cache *ResourceCache) context.Context {
+	return context.WithValue(ctx, LulaResourceCache, cache)
+}
+
+// ResourceCacheFromContext returns the resource cache of the context, or nil if
+// there is none
+func ResourceCacheFromContext(ctx context.Context) *ResourceCache {
+	cache, _ := ctx.Value(LulaResourceCache).(*ResourceCache)
+	return cache
+}
+
+// Get returns the value cached under key, calling collect to populate it on a
+// miss. Concurrent callers of the same key wait for the first collection, or
+// until their ctx is done. Errors are not cached, so a failed collection is
+// retried by the next caller. A nil cache always calls collect.
+func (c *ResourceCache) Get(ctx context.Context, key string, collect func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return collect()
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err == nil {
+			message.Debugf("Resource cache hit: %s", key)
+			return entry.value, nil
+		}
+		return c.Get(ctx, key, collect)
+	}
+	entry := &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	message.Debugf("Resource cache miss: %s", key)
+	entry.value, entry.err = collect()
+	if entry.err != nil {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.value, entry.err
+}
+
+// GetDomainResources returns the resources of the domain from the resource
+// cache of the context. Executable domains can change what they collect, so
+// their resources are always collected.
+func GetDomainResources(ctx context.Context, domain Domain) (DomainResources, error) {
+	cache := ResourceCacheFromContext(ctx)
+	if cache == nil || domain.IsExecutable() {
+		return domain.GetResources(ctx)
+	}
+
+	key, err := domainCacheKey(ctx, domain)
+	if err != nil {
+		message.Debugf("Resources of domain %T cannot be cached: %v", domain, err)
+		return domain.GetResources(ctx)
+	}
+	value, err := cache.Get(ctx, key, func() (interface{}, error) {
+		return domain.GetResources(ctx)
+	})
+	resources, _ := value.(DomainResources)
+	return resources, err
+}
+
+// domainCacheKey normalizes the domain spec, and the work directory its paths
+// are relative to, into a cache key
+func domainCacheKey(ctx context.Context, domain Domain) (string, error) {
+	spec, err := json.Marshal(domain)
+	if err != nil {
+		return "", err
+	}
+	// a domain without exported fields can't be told apart from others of its type
+	if string(spec) == "{}" || string(spec) == "null" {
+		return "", fmt.Errorf("domain has no spec")
+	}
+	workDir, _ := ctx.Value(LulaValidationWorkDir).(string)
+	return fmt.Sprintf("domain %T %s %s", domain, workDir, spec), nil
+}
@@ -0,0 +1,162 @@
+package types_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defenseunicorns/lula/src/types"
+)
+
+// countingDomain counts the collections of its resources
+type countingDomain struct {
+	Spec       string `json:"spec,omitempty"`
+	executable bool
+	calls      *atomic.Int32
+}
+
+func (d countingDomain) GetResources(context.Context) (types.DomainResources, error) {
+	d.calls.Add(1)
+	return types.DomainResources{"spec": d.Spec}, nil
+}
+
+func (d countingDomain) IsExecutable() bool { return d.executable }
+
+func TestResourceCacheGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hit", func(t *testing.T) {
+		cache := types.NewResourceCache()
+		calls := 0
+		collect := func() (interface{}, error) {
+			calls++
+			return calls, nil
+		}
+
+		for range 3 {
+			value, err := cache.Get(context.Background(), "pods", collect)
+			require.NoError(t, err)
+			require.Equal(t, 1, value)
+		}
+		value, err := cache.Get(context.Background(), "secrets", collect)
+		require.NoError(t, err)
+		require.Equal(t, 2, value)
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		cache := types.NewResourceCache()
+		_, err := cache.Get(context.Background(), "pods", func() (interface{}, error) { return nil, errors.New("unavailable") })
+		require.Error(t, err)
+
+		value, err := cache.Get(context.Background(), "pods", func() (interface{}, error) { return "pods", nil })
+		require.NoError(t, err)
+		require.Equal(t, "pods", value)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		cache := types.NewResourceCache()
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := cache.Get(context.Background(), "pods", func() (interface{}, error) {
+					calls.Add(1)
+					return "pods", nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, "pods", value)
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("waiter deadline", func(t *testing.T) {
+		cache := types.NewResourceCache()
+		collecting := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		go func() {
+			_, _ = cache.Get(context.Background(), "pods", func() (interface{}, error) {
+				close(collecting)
+				<-release
+				return "pods", nil
+			})
+		}()
+		<-collecting
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := cache.Get(ctx, "pods", func() (interface{}, error) {
+			t.Error("collected while another collection was in progress")
+			return nil, nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var cache *types.ResourceCache
+		calls := 0
+		for range 2 {
+			_, err := cache.Get(context.Background(), "pods", func() (interface{}, error) {
+				calls++
+				return nil, nil
+			})
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, calls)
+	})
+}
+
+func TestGetDomainResources(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	pods := countingDomain{Spec: "pods", calls: &calls}
+	ctx := types.WithResourceCache(context.Background(), types.NewResourceCache())
+
+	for range 2 {
+		resources, err := types.GetDomainResources(ctx, pods)
+		require.NoError(t, err)
+		require.Equal(t, types.DomainResources{"spec": "pods"}, resources)
+	}
+	require.Equal(t, int32(1), calls.Load())
+
+	// a different spec is collected
+	_, err := types.GetDomainResources(ctx, countingDomain{Spec: "secrets", calls: &calls})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+
+	// paths of the same spec are relative to the work directory
+	_, err = types.GetDomainResources(context.WithValue(ctx, types.LulaValidationWorkDir, "other"), pods)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	// executable domains are always collected
+	executable := countingDomain{Spec: "pods", executable: true, calls: &calls}
+	for range 2 {
+		_, err := types.GetDomainResources(ctx, executable)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(5), calls.Load())
+
+	// domains without a spec are always collected
+	unkeyed := countingDomain{calls: &calls}
+	_, err = types.GetDomainResources(ctx, unkeyed)
+	require.NoError(t, err)
+	require.Equal(t, int32(6), calls.Load())
+
+	// without a cache, domains are always collected
+	_, err = types.GetDomainResources(context.Background(), pods)
+	require.NoError(t, err)
+	require.Equal(t, int32(7), calls.Load())
+}
@@ -5,6 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
 
 	"github.com/defenseunicorns/lula/src/pkg/message"
 )
@@ -14,6 +20,7 @@ var (
 	ErrExecutionNotAllowed = errors.New("execution not allowed")
 	ErrDomainGetResources  = errors.New("domain GetResources error")
 	ErrProviderEvaluate    = errors.New("provider Evaluate error")
+	ErrValidationTimeout   = errors.New("validation timed out")
 )
 
 type LulaValidationType string
@@ -42,6 +49,9 @@ type LulaValidation struct {
 	// LulaValidationType is the type of validation that is being performed
 	LulaValidationType LulaValidationType
 
+	// Timeout is the maximum duration of the validation, overriding the default timeout if set
+	Timeout time.Duration
+
 	// Evaluated is a boolean that represents if the validation has been evaluated
 	Evaluated bool
 
@@ -77,6 +87,7 @@ type lulaValidationOptions struct {
 	isInteractive    bool
 	onlyResources    bool
 	spinner          *message.Spinner
+	timeout          time.Duration
 }
 
 type LulaValidationOption func(*lulaValidationOptions)
@@ -109,6 +120,13 @@ func WithSpinner(spinner *message.Spinner) LulaValidationOption {
 	}
 }
 
+// WithTimeout sets the timeout of validations that don't set their own, zero for no timeout
+func WithTimeout(timeout time.Duration) LulaValidationOption {
+	return func(opts *lulaValidationOptions) {
+		opts.timeout = timeout
+	}
+}
+
 // RequireExecutionConfirmation is a function that returns a boolean indicating if the validation requires confirmation before execution
 func GetResourcesOnly(onlyResources bool) LulaValidationOption {
 	return func(opts *lulaValidationOptions) {
@@ -140,26 +158,43 @@ func (v *LulaValidation) Validate(ctx context.Context, opts ...LulaValidationOpt
 			opt(config)
 		}
 
-		// Check if confirmation needed before execution
-		if config.staticResources == nil {
-			if (*v.Domain).IsExecutable() && !config.executionAllowed {
-				if config.isInteractive {
-					// Run confirmation user prompt
-					if confirm := message.PromptForConfirmation(config.spinner); !confirm {
-						return fmt.Errorf("%w: requested execution denied", ErrExecutionNotAllowed)
-					}
-				} else {
-					return fmt.Errorf("%w: non-interactive execution not allowed", ErrExecutionNotAllowed)
+		// Check if confirmation needed before execution, an executable provider runs whenever the
+		// resources are evaluated while an executable domain only runs to collect them
+		executable := v.Provider != nil && (*v.Provider).IsExecutable() && (config.staticResources != nil || !config.onlyResources)
+		if config.staticResources == nil && v.Domain != nil && (*v.Domain).IsExecutable() {
+			executable = true
+		}
+		if executable && !config.executionAllowed {
+			if config.isInteractive {
+				// Run confirmation user prompt
+				if confirm := message.PromptForConfirmation(config.spinner); !confirm {
+					return fmt.Errorf("%w: requested execution denied", ErrExecutionNotAllowed)
 				}
+			} else {
+				return fmt.Errorf("%w: non-interactive execution not allowed", ErrExecutionNotAllowed)
 			}
 		}
 
+		// Bound the resource collection and evaluation, but not the confirmation, by the timeout
+		timeout := config.timeout
+		if v.Timeout > 0 {
+			timeout = v.Timeout
+		}
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
 		// Get the resources
 		if config.staticResources != nil {
 			resources = config.staticResources
 		} else {
-			resources, err = (*v.Domain).GetResources(ctx)
+			resources, err = GetDomainResources(ctx, *v.Domain)
 			if err != nil {
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("%w after %s getting resources: %v", ErrValidationTimeout, timeout, err)
+				}
 				return fmt.Errorf("%w: %v", ErrDomainGetResources, err)
 			}
 			if config.onlyResources {
@@ -170,6 +205,9 @@ func (v *LulaValidation) Validate(ctx context.Context, opts ...LulaValidationOpt
 		// Perform the evaluation using the provider
 		result, err = (*v.Provider).Evaluate(ctx, resources)
 		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%w after %s evaluating: %v", ErrValidationTimeout, timeout, err)
+			}
 			return fmt.Errorf("%w: %v", ErrProviderEvaluate, err)
 		}
 	}
@@ -177,7 +215,7 @@ func (v *LulaValidation) Validate(ctx context.Context, opts ...LulaValidationOpt
 }
 
 // RunTests executes any tests defined in the validation and returns a report of the results
-func (v *LulaValidation) RunTests(ctx context.Context, saveResources bool) (*LulaValidationTestReport, error) {
+func (v *LulaValidation) RunTests(ctx context.Context, saveResources bool, opts ...LulaValidationOption) (*LulaValidationTestReport, error) {
 	if v.DomainResources == nil {
 		return nil, fmt.Errorf("domain resources are nil, tests cannot be run")
 	}
@@ -195,7 +233,7 @@ func (v *LulaValidation) RunTests(ctx context.Context, saveResources bool) (*Lul
 				}
 
 				// Execute the test
-				testResult, err := d.ExecuteTest(ctx, testValidation, testResources, saveResources)
+				testResult, err := d.ExecuteTest(ctx, testValidation, testResources, saveResources, opts...)
 				if err != nil {
 					return nil, err
 				}
@@ -212,7 +250,15 @@ func (v *LulaValidation) RunTests(ctx context.Context, saveResources bool) (*Lul
 
 // Check if the validation requires confirmation before possible execution code is run
 func (v *LulaValidation) RequireExecutionConfirmation() (confirm bool) {
-	return !(*v.Domain).IsExecutable()
+	return !v.IsExecutable()
+}
+
+// IsExecutable returns true if the domain or the provider of the validation is executable
+func (v *LulaValidation) IsExecutable() bool {
+	if v.Domain != nil && (*v.Domain).IsExecutable() {
+		return true
+	}
+	return v.Provider != nil && (*v.Provider).IsExecutable()
 }
 
 // Return domain resources as a json []byte
@@ -235,17 +281,87 @@ type Domain interface {
 
 type Provider interface {
 	Evaluate(context.Context, DomainResources) (Result, error)
+	IsExecutable() bool
 }
 
 // native type for conversion to targeted report format
 type Result struct {
-	UUID         string            `json:"uuid" yaml:"uuid"`
-	ControlId    string            `json:"control-id" yaml:"control-id"`
-	Description  string            `json:"description" yaml:"description"`
-	Passing      int               `json:"passing" yaml:"passing"`
-	Failing      int               `json:"failing" yaml:"failing"`
-	State        string            `json:"state" yaml:"state"`
-	Observations map[string]string `json:"observations" yaml:"observations"`
+	UUID         string                 `json:"uuid" yaml:"uuid"`
+	ControlId    string                 `json:"control-id" yaml:"control-id"`
+	Description  string                 `json:"description" yaml:"description"`
+	Passing      int                    `json:"passing" yaml:"passing"`
+	Failing      int                    `json:"failing" yaml:"failing"`
+	State        string                 `json:"state" yaml:"state"`
+	Observations map[string]interface{} `json:"observations" yaml:"observations"`
+	Resources    []ResourceResult       `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// ResourceResult is the outcome of a validation for a single resource evaluated by the provider
+type ResourceResult struct {
+	ID        string `json:"id" yaml:"id"`
+	Kind      string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Pass      bool   `json:"pass" yaml:"pass"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// FormatObservation renders an observation value as text, strings are used as-is, lists and
+// objects are rendered as YAML and any other value as JSON
+func FormatObservation(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	if value != nil {
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			data, err := yaml.Marshal(value)
+			if err == nil {
+				return strings.TrimSuffix(string(data), "\n")
+			}
+			message.Debugf("Error marshalling observation to YAML: %v", err)
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// FormatObservations renders the observations as "key: value" lines ordered by key, lists and
+// objects are rendered as indented YAML on the lines following their key
+func FormatObservations(observations map[string]interface{}) string {
+	keys := make([]string, 0, len(observations))
+	for k := range observations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var formatted strings.Builder
+	for _, k := range keys {
+		value := FormatObservation(observations[k])
+		if isObservationBlock(observations[k]) {
+			fmt.Fprintf(&formatted, "%s:\n  %s\n", k, strings.ReplaceAll(value, "\n", "\n  "))
+			continue
+		}
+		fmt.Fprintf(&formatted, "%s: %s\n", k, value)
+	}
+	return formatted.String()
+}
+
+// isObservationBlock returns true for non-empty lists and objects, which are rendered as a YAML block
+func isObservationBlock(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	default:
+		return false
+	}
 }
 
 func deepCopyMap(input map[string]interface{}) map[string]interface{} {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -148,7 +149,7 @@ func TestRunTests(t *testing.T) {
 						TestName: "test-modify-name",
 						Result:   "not-satisfied",
 						Pass:     true,
-						Remarks:  map[string]string{},
+						Remarks:  map[string]interface{}{},
 					},
 				},
 			},
@@ -194,7 +195,7 @@ func TestRunTests(t *testing.T) {
 						TestName: "test-modify-name",
 						Result:   "not-satisfied",
 						Pass:     true,
-						Remarks: map[string]string{
+						Remarks: map[string]interface{}{
 							"validate.msg": "another-resource",
 						},
 					},
@@ -240,7 +241,7 @@ func TestRunTests(t *testing.T) {
 						TestName:          "test-modify-name",
 						Result:            "not-satisfied",
 						Pass:              true,
-						Remarks:           map[string]string{},
+						Remarks:           map[string]interface{}{},
 						TestResourcesPath: tmpDirName + "/test-modify-name.json",
 					},
 				},
@@ -298,13 +299,13 @@ func TestRunTests(t *testing.T) {
 						TestName: "test-modify-name",
 						Pass:     true,
 						Result:   "not-satisfied",
-						Remarks:  map[string]string{},
+						Remarks:  map[string]interface{}{},
 					},
 					{
 						TestName: "test-add-another-field",
 						Pass:     true,
 						Result:   "satisfied",
-						Remarks:  map[string]string{},
+						Remarks:  map[string]interface{}{},
 					},
 				},
 			},
@@ -321,3 +322,121 @@ func TestRunTests(t *testing.T) {
 		})
 	}
 }
+
+// blockingDomain collects its resources once ctx is done
+type blockingDomain struct{}
+
+func (blockingDomain) GetResources(ctx context.Context) (types.DomainResources, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingDomain) IsExecutable() bool { return false }
+
+// gatedDomain collects its resources once released, regardless of ctx
+type gatedDomain struct {
+	Spec       string `json:"spec"`
+	collecting chan struct{}
+	release    chan struct{}
+}
+
+func (d gatedDomain) GetResources(context.Context) (types.DomainResources, error) {
+	close(d.collecting)
+	<-d.release
+	return types.DomainResources{"spec": d.Spec}, nil
+}
+
+func (gatedDomain) IsExecutable() bool { return false }
+
+func TestValidateTimeout(t *testing.T) {
+	t.Parallel()
+
+	newValidation := func(timeout time.Duration) *types.LulaValidation {
+		var domain types.Domain = blockingDomain{}
+		return &types.LulaValidation{Name: "blocking", Domain: &domain, Timeout: timeout}
+	}
+
+	t.Run("default timeout", func(t *testing.T) {
+		err := newValidation(0).Validate(context.Background(), types.WithTimeout(10*time.Millisecond))
+		require.ErrorIs(t, err, types.ErrValidationTimeout)
+	})
+
+	t.Run("validation timeout overrides the default", func(t *testing.T) {
+		start := time.Now()
+		err := newValidation(10*time.Millisecond).Validate(context.Background(), types.WithTimeout(time.Hour))
+		require.ErrorIs(t, err, types.ErrValidationTimeout)
+		require.Less(t, time.Since(start), time.Minute)
+	})
+
+	t.Run("shared collection in progress", func(t *testing.T) {
+		ctx := types.WithResourceCache(context.Background(), types.NewResourceCache())
+		collecting := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		var domain types.Domain = gatedDomain{Spec: "pods", collecting: collecting, release: release}
+
+		// the first validation has no timeout and holds the collection
+		go func() {
+			_ = (&types.LulaValidation{Name: "first", Domain: &domain}).Validate(ctx, types.GetResourcesOnly(true))
+		}()
+		<-collecting
+
+		start := time.Now()
+		err := (&types.LulaValidation{Name: "second", Domain: &domain, Timeout: 10 * time.Millisecond}).Validate(ctx)
+		require.ErrorIs(t, err, types.ErrValidationTimeout)
+		require.Less(t, time.Since(start), time.Minute)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := newValidation(0).Validate(ctx)
+		require.ErrorIs(t, err, types.ErrDomainGetResources)
+		require.NotErrorIs(t, err, types.ErrValidationTimeout)
+	})
+}
+
+func TestFormatObservations(t *testing.T) {
+	t.Parallel()
+
+	observations := map[string]interface{}{
+		"name":      "lula",
+		"count":     json.Number("2"),
+		"offenders": []interface{}{"pod-a", "pod-b"},
+		"summary":   map[string]interface{}{"checked": float64(3)},
+		"empty":     []interface{}{},
+	}
+
+	want := "count: 2\n" +
+		"empty: []\n" +
+		"name: lula\n" +
+		"offenders:\n  - pod-a\n  - pod-b\n" +
+		"summary:\n  checked: 3\n"
+	require.Equal(t, want, types.FormatObservations(observations))
+}
+
+func TestValidateExecutableProvider(t *testing.T) {
+	t.Parallel()
+
+	newValidation := func() *types.LulaValidation {
+		provider, err := opa.CreateOpaProvider(context.Background(), &opa.OpaSpec{
+			Rego:       "package validate\n\ndefault validate = true",
+			Executable: true,
+		})
+		require.NoError(t, err)
+		return &types.LulaValidation{Name: "executable", Provider: &provider}
+	}
+	resources := types.DomainResources{"pod": map[string]interface{}{"kind": "Pod"}}
+
+	t.Run("static resources require execution to be allowed", func(t *testing.T) {
+		err := newValidation().Validate(context.Background(), types.WithStaticResources(resources))
+		require.ErrorIs(t, err, types.ErrExecutionNotAllowed)
+	})
+
+	t.Run("static resources with execution allowed", func(t *testing.T) {
+		validation := newValidation()
+		err := validation.Validate(context.Background(), types.WithStaticResources(resources), types.ExecutionAllowed(true))
+		require.NoError(t, err)
+		require.Equal(t, 1, validation.Result.Passing)
+	})
+}
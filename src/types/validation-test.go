@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/defenseunicorns/lula/src/internal/transform"
 	"github.com/defenseunicorns/lula/src/pkg/message"
@@ -31,8 +32,8 @@ func (l *LulaValidationTest) ValidateData() error {
 		return fmt.Errorf("name is empty")
 	}
 
-	if l.ExpectedResult != "satisfied" && l.ExpectedResult != "not-satisfied" {
-		return fmt.Errorf("expected-result must be satisfied or not-satisfied")
+	if l.ExpectedResult != "satisfied" && l.ExpectedResult != "not-satisfied" && l.ExpectedResult != "not-applicable" {
+		return fmt.Errorf("expected-result must be satisfied, not-satisfied or not-applicable")
 	}
 
 	for _, change := range l.Changes {
@@ -68,7 +69,7 @@ func (c *LulaValidationTestChange) validateData() error {
 }
 
 // ExecuteTest executes a single LulaValidationTest
-func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *LulaValidation, resources map[string]interface{}, saveResources bool) (*LulaValidationTestResult, error) {
+func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *LulaValidation, resources map[string]interface{}, saveResources bool, opts ...LulaValidationOption) (*LulaValidationTestResult, error) {
 	if d.Test == nil {
 		return nil, fmt.Errorf("test is nil")
 	}
@@ -80,7 +81,7 @@ func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *Lu
 	tt, err := transform.CreateTransformTarget(resources)
 	if err != nil {
 		d.Result.Pass = false
-		d.Result.Remarks = map[string]string{
+		d.Result.Remarks = map[string]interface{}{
 			"error creating transform target": err.Error(),
 		}
 		return d.Result, nil
@@ -90,7 +91,7 @@ func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *Lu
 		resources, err = tt.ExecuteTransform(c.Path, c.Type, c.Value, c.ValueMap)
 		if err != nil {
 			d.Result.Pass = false
-			d.Result.Remarks = map[string]string{
+			d.Result.Remarks = map[string]interface{}{
 				"error executing transform": err.Error(),
 			}
 			return d.Result, nil
@@ -114,10 +115,10 @@ func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *Lu
 		}
 	}
 
-	err = validation.Validate(ctx, WithStaticResources(resources))
+	err = validation.Validate(ctx, slices.Concat(opts, []LulaValidationOption{WithStaticResources(resources)})...)
 	if err != nil {
 		d.Result.Pass = false
-		d.Result.Remarks = map[string]string{
+		d.Result.Remarks = map[string]interface{}{
 			"error running validation": err.Error(),
 		}
 		return d.Result, nil
@@ -125,7 +126,9 @@ func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *Lu
 
 	// Update test report
 	result := "not-satisfied"
-	if validation.Result.Passing > 0 {
+	if validation.Result.State == "not-applicable" {
+		result = "not-applicable"
+	} else if validation.Result.Passing > 0 {
 		result = "satisfied"
 	}
 	d.Result.Result = result
@@ -138,11 +141,11 @@ func (d *LulaValidationTestData) ExecuteTest(ctx context.Context, validation *Lu
 // LulaValidationTestResult is a struct that contains the details of the results of the test performed
 // on the LulaValidation
 type LulaValidationTestResult struct {
-	TestName          string            `json:"test-name" yaml:"test-name"`
-	Pass              bool              `json:"pass" yaml:"pass"`
-	Result            string            `json:"result" yaml:"result"`
-	Remarks           map[string]string `json:"remarks,omitempty" yaml:"remarks,omitempty"`
-	TestResourcesPath string            `json:"test-resources-path,omitempty" yaml:"test-resources-path,omitempty"`
+	TestName          string                 `json:"test-name" yaml:"test-name"`
+	Pass              bool                   `json:"pass" yaml:"pass"`
+	Result            string                 `json:"result" yaml:"result"`
+	Remarks           map[string]interface{} `json:"remarks,omitempty" yaml:"remarks,omitempty"`
+	TestResourcesPath string                 `json:"test-resources-path,omitempty" yaml:"test-resources-path,omitempty"`
 }
 
 // LulaValidationTestReport contains the report of all the tests performed on a LulaValidation
@@ -185,7 +188,7 @@ func (r *LulaValidationTestReport) PrintReport() {
 			message.Infof("Result: %s", testResult.Result)
 		}
 		for remark, value := range testResult.Remarks {
-			message.Infof("--> %s: %s", remark, value)
+			message.Infof("--> %s: %s", remark, FormatObservation(value))
 		}
 		if testResult.TestResourcesPath != "" {
 			message.Infof("Test Resources File Path: %s", testResult.TestResourcesPath)